@@ -6,4 +6,5 @@ require (
 	github.com/integrii/flaggy v1.4.4
 	github.com/jroimartin/gocui v0.5.0
 	github.com/logrusorgru/aurora v2.0.3+incompatible
+	github.com/nsf/termbox-go v1.1.1
 )
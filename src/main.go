@@ -1,14 +1,25 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"github.com/integrii/flaggy"
+	"os"
+	"path/filepath"
 	"simlife/src/universe"
 	"simlife/src/view"
+	"strconv"
 	"strings"
 	"time"
 )
 
+//the sweep mode seeds count by default
+const defSeeds = 100
+
+//the density option value standing for the suggested density of the rule
+const ruleDensity = -1
+
 var (
 	testSample = [][]int{
 		{1, 1}, {1, 2},
@@ -19,24 +30,102 @@ var (
 		{5, 3},
 	}
 
-	engines = map[string]func(o *universe.Options, stateCh chan universe.Status) universe.Universe{
-		"base": func(o *universe.Options, stateCh chan universe.Status) universe.Universe {
+	engines = map[string]func(o *universe.Options, stateCh chan universe.Status) (universe.Universe, error){
+		"base": func(o *universe.Options, stateCh chan universe.Status) (universe.Universe, error) {
 			return universe.NewBaseUniverse(o, stateCh)
 		},
 		"simple":        universe.NewSimpleUniverse,
 		"smallBuff":     universe.NewSmallBuffUniverse,
 		"multithreaded": universe.NewMultithreadedUniverse,
+		"hashlife":      universe.NewHashlifeUniverse,
+	}
+
+	//demos are the fields settled by the --demo option
+	demos = map[string]demo{
+		"glider-fleet": {
+			descr:    "the reproducible fleet of gliders by the seed and the density crossing the torus",
+			boundary: universe.BoundaryTorus,
+			settle:   settleGliderFleet,
+		},
 	}
+
+	onMaxStepsValues = []string{universe.OnMaxStepsStop, universe.OnMaxStepsContinue, universe.OnMaxStepsReset}
 )
 
+//demo is the field settled by the --demo option with the boundary it needs
+type demo struct {
+	descr    string
+	boundary universe.Boundary
+	settle   func(u universe.Universe, eo *EnvOptions)
+}
+
 type EnvOptions struct {
 	interactive bool
+	sweep       bool
+	seeds       int
+	sortBy      string //the sweep results order, one of universe.SweepSorts
+	field       string //the field encoded by universe.EncodeArea
+	bench       int    //the steps of the engines benchmark, 0 - no benchmark
+	lifespan    bool   //the settled field is analyzed until it stabilizes instead of the simulation
+	serve       string //the Unix socket the attached UIs watch the simulation through
+	sized       bool   //the field size is set by the flags, it isn't taken from the loaded pattern
+	attach      string //the Unix socket of the served simulation the UI is attached to
+	seed        int64
+	density     float64
 	randomData  bool
+	noBell      bool
+	autostart   bool
+	maxCells    int
+	maxPeriod   int
+	stepBudget  int //the milliseconds of the steps per running tick, 0 - one step per tick
+	history     int //the past generations kept for scrubbing, -1 - the default of the mode
+	historyMB   int //the megabytes the past generations take at most
+	onMaxSteps  string
+	rule        string
+	boundary    string
+	origin      string
+	layout      string
+	rle         string
+	patternX    int
+	patternY    int
+	anchor      string             //the point of the pattern placed at patternX, patternY and at the UI cursor, one of universe.Anchors
+	merge       bool               //the layout and the pattern are added to the field instead of replacing it
+	pattern     *universe.Template //the pattern loaded from the rle or the image file
+	patternPath string             //the file the pattern is loaded from
+	image       string
+	threshold   int //the luminance below which the image pixel is live
+	split       []string
 	engine      string
+	logFile     string
+	printKeys   bool
+	printCaps   bool
+	demo        string
+	status      string //the comma separated status panel metrics
+	clusters    int    //the neighbours connectivity of the clusters counted in the console UI, 4 or 8
+	speeds      string //the comma separated name=interval speed presets
+	glyphs      string //the comma separated state=glyph:color glyphs of the multi-color rules
+	//the field is saved every autosave generations or every autosaveEvery, the latest save can be resumed on start
+	autosave      int
+	autosaveEvery time.Duration
+	autosaveDir   string
+	resume        bool //the pattern is loaded from the autosave and placed where it was saved
 }
 
 func main() {
-	eo, uo := initOptions()
+	eo, uo, vo := initOptions()
+
+	if eo.sweep {
+		runSweep(uo, eo.seeds, eo.sortBy)
+		return
+	}
+	if eo.bench > 0 {
+		runBench(uo, eo.seed, eo.bench)
+		return
+	}
+	if eo.attach != "" {
+		runAttached(eo.attach)
+		return
+	}
 
 	var stateCh chan universe.Status
 
@@ -44,28 +133,94 @@ func main() {
 		stateCh = make(chan universe.Status, 10) //the buffered channel to getting the universe status
 	}
 
-	u := engines[eo.engine](uo, stateCh)
-
-	u.AddTemplate(
-		universe.Template{
-			Name:        "testSample1",
-			Descr:       "the test sample with 3 stable patterns",
-			Coordinates: testSample,
-		})
+	if eo.randomData && eo.seed == 0 && len(eo.split) > 0 {
+		//the split universes are settled with the same random area
+		eo.seed = time.Now().UnixNano()
+	}
+	u := newUniverse(eo, uo, stateCh)
+	if eo.lifespan {
+		runLifespan(u, uo.MaxSteps, vo.Summary)
+		return
+	}
 
-	if eo.randomData {
-		u.SettleWithRandomData()
-	} else {
-		u.SettleTemplate("testSample1")
+	var eventLog *view.EventLog
+	if eo.logFile != "" {
+		f, err := os.OpenFile(eo.logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		eventLog = view.NewEventLog(f)
+	}
+	if eo.serve != "" {
+		server, err := view.NewServer(eo.serve, capabilities(eo.engine))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer server.Close()
+		u.RegisterViewer(server)
+		server.Start()
+	}
+	var autosave *view.Autosave
+	if eo.autosave > 0 || eo.autosaveEvery > 0 {
+		autosave = view.NewAutosave(eo.autosaveDir, eo.autosave, eo.autosaveEvery)
+		u.RegisterViewer(autosave)
 	}
 
 	if eo.interactive {
-		v := view.NewConsoleUI()
-		u.RegisterViewer(v)
+		universes := []universe.Universe{u}
+		for _, rule := range eo.split {
+			o := *uo
+			o.Advanced = make(map[string]interface{}, len(uo.Advanced)+1)
+			for k, v := range uo.Advanced {
+				o.Advanced[k] = v
+			}
+			o.Advanced[universe.AdvRule] = rule
+			universes = append(universes, newUniverse(eo, &o, stateCh))
+		}
+		v, err := view.NewConsoleUI(vo)
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("The console UI needs the interactive terminal, use the \"run\" mode for the console output")
+			os.Exit(1)
+		}
+		for _, u := range universes {
+			if eventLog != nil {
+				u.RegisterViewer(eventLog)
+			}
+			u.RegisterViewer(v)
+			if autostart, _ := u.Options().Advanced[universe.AdvAutostart].(bool); autostart {
+				u.Run()
+			}
+		}
 		v.Start()
-		u.Close()
+		for _, u := range universes {
+			u.Close()
+		}
+		if v.Err() != nil {
+			fmt.Println(v.Err())
+		}
+		if autosave != nil {
+			//the pending save is written before exiting
+			autosave.Close()
+			if autosave.Err() != nil {
+				fmt.Println("autosave failed:", autosave.Err())
+			}
+		}
+		if eventLog != nil {
+			//the queued events are written before exiting
+			eventLog.Close()
+			if eventLog.Err() != nil {
+				fmt.Println("event log failed:", eventLog.Err())
+			}
+		}
 	} else {
-		v := view.NewConsoleOut()
+		if eventLog != nil {
+			u.RegisterViewer(eventLog)
+		}
+		v := view.NewConsoleOut(vo)
 		u.RegisterViewer(v)
 		v.Start()
 		u.Run()
@@ -77,20 +232,40 @@ func main() {
 		}
 		u.Close()
 		close(stateCh)
+		if autosave != nil {
+			autosave.Close()
+		}
+		if eventLog != nil {
+			eventLog.Close()
+		}
 		//waiting for all final output printing
 		time.Sleep(time.Millisecond * 200)
 	}
 
 }
 
-func initOptions() (eo *EnvOptions, uo *universe.Options) {
+func initOptions() (eo *EnvOptions, uo *universe.Options, vo *view.Options) {
 
 	uo = &universe.DefaultUniverseOptions
+	vo = &view.DefaultOptions
 	engineNames := make([]string, 0, len(engines))
 	for k := range engines {
 		engineNames = append(engineNames, k)
 	}
-	eo = &EnvOptions{engine: "base"}
+	demoNames := make([]string, 0, len(demos))
+	for k := range demos {
+		demoNames = append(demoNames, k)
+	}
+	anchorNames := make([]string, 0, len(universe.Anchors))
+	for _, a := range universe.Anchors {
+		anchorNames = append(anchorNames, string(a))
+	}
+	boundaryNames := make([]string, 0, len(universe.Boundaries))
+	for _, b := range universe.Boundaries {
+		boundaryNames = append(boundaryNames, string(b))
+	}
+	eo = &EnvOptions{engine: "base", seeds: defSeeds, density: ruleDensity, threshold: universe.DefImageThreshold,
+		history: -1, historyMB: universe.DefHistoryMB, clusters: int(universe.Connectivity8)}
 	flaggy.DefaultParser.ShowHelpOnUnexpected = true
 
 	runMode := flaggy.NewSubcommand("run")
@@ -98,22 +273,270 @@ func initOptions() (eo *EnvOptions, uo *universe.Options) {
 
 	uiMode := flaggy.NewSubcommand("ui")
 	uiMode.Description = "Run with console UI"
+	uiMode.StringSlice(&eo.split, "", "split", "Show one more universe with the rule side by side, can be repeated")
+
+	sweepMode := flaggy.NewSubcommand("sweep")
+	sweepMode.Description = "Run the random seeds up to maxSteps and list the longest living ones"
+	sweepMode.Int(&eo.seeds, "", "seeds", "The count of the random seeds to run")
+	sweepMode.String(&eo.sortBy, "", "sortBy", "The results order ["+strings.Join(universe.SweepSorts, "|")+"]")
+
+	attachMode := flaggy.NewSubcommand("attach")
+	attachMode.Description = "Attach the console UI to the simulation served by --serve, detaching leaves it running"
+	attachMode.String(&eo.attach, "", "socket", "The Unix socket of the served simulation")
 
 	flaggy.AttachSubcommand(runMode, 1)
 	flaggy.AttachSubcommand(uiMode, 1)
+	flaggy.AttachSubcommand(sweepMode, 1)
+	flaggy.AttachSubcommand(attachMode, 1)
 
 	flaggy.Int(&uo.Width, "x", "width", "Width of a simulation field")
 	flaggy.Int(&uo.Height, "y", "height", "Height of a simulation field")
 	flaggy.Duration(&uo.Interval, "i", "interval", "Simulation speed (interval between the steps) in format the number with 'ms' suffix, for example 150ms")
 	flaggy.Int(&uo.MaxSteps, "s", "maxSteps", "Limit the simulation to maxSteps")
+	flaggy.String(&eo.rule, "", "rule", "The rule in B/S notation, for example B36/S23, or quadlife for the four-color variant (default B3/S23)")
+	flaggy.String(&eo.boundary, "", "boundary", "The field edges behaviour ["+strings.Join(boundaryNames, "|")+"] (default none)")
+	flaggy.String(&eo.origin, "", "origin", "The origin of the layout and the displayed coordinates ["+universe.OriginTopLeft+"|"+universe.OriginBottomLeft+"]")
+	flaggy.String(&eo.onMaxSteps, "", "onMaxSteps", "What to do when maxSteps is reached ["+strings.Join(onMaxStepsValues, "|")+"]")
+	flaggy.Int(&eo.maxCells, "", "maxCells", "Stop the simulation when the live cells count exceeds maxCells, 0 - unlimited")
+	flaggy.Int(&eo.maxPeriod, "", "maxPeriod", "Stop the simulation when the field repeats within maxPeriod steps, 0 - no cycle detection")
+	flaggy.Int(&eo.history, "", "history", "The count of the past generations kept for scrubbing with [ and ], 0 - none, -1 - "+strconv.Itoa(universe.DefHistorySize)+" in the ui mode and none in the others")
+	flaggy.Int(&eo.historyMB, "", "historyMB", "The megabytes the past generations kept for scrubbing take at most, the oldest ones are dropped beyond, 0 - unlimited")
+	flaggy.Int(&eo.stepBudget, "", "stepBudget", "Do as many steps as fit in stepBudget milliseconds per interval tick, the interval is counted from the tick start, 0 - one step per tick")
+	flaggy.Bool(&eo.autostart, "", "autostart", "Start the simulation right after the seeding (ui mode)")
 	flaggy.Bool(&eo.randomData, "r", "random", "Settle with random data")
+	flaggy.Int64(&eo.seed, "", "seed", "Settle with the random area of the seed (see the sweep mode)")
+	flaggy.Int(&eo.bench, "", "bench", "Run the random area of the seed for bench steps on each engine, print the speed and check the final fields match")
+	flaggy.Bool(&eo.lifespan, "", "lifespan", "Run the settled field until it stabilizes or the steps limit, print the generations and the final population (JSON with --summary)")
+	flaggy.Float64(&eo.density, "", "density", "The share of the live cells in the seeded random area, -1 - suggested by the rule (0.3 for B3/S23, 0.05 for B2/S)")
+	flaggy.String(&eo.rle, "", "rle", "Settle with the pattern from the RLE file")
+	flaggy.String(&eo.field, "", "field", "Settle with the field encoding listed by the sweep mode")
+	flaggy.String(&eo.image, "", "image", "Settle with the PNG or JPEG image, the dark pixels are live, the image is not scaled and is cropped by the field")
+	flaggy.Int(&eo.threshold, "", "threshold", "The luminance 0-255 below which the --image pixel is live")
+	flaggy.Int(&eo.patternX, "", "patternX", "The x of the --rle or --image pattern placement, measured from the origin")
+	flaggy.Int(&eo.patternY, "", "patternY", "The y of the --rle or --image pattern placement, measured from the origin")
+	flaggy.String(&eo.anchor, "", "anchor", "The point of the --rle or --image pattern placed at patternX, patternY and at the console UI cursor ["+strings.Join(anchorNames, "|")+"] (default "+string(universe.AnchorTopLeft)+")")
+	flaggy.Int(&eo.autosave, "", "autosave", "Save the field every autosave generations, the latest save is offered to resume on start (ui mode)")
+	flaggy.Duration(&eo.autosaveEvery, "", "autosaveEvery", "Save the field every interval, for example 30s")
+	flaggy.String(&eo.autosaveDir, "", "autosaveDir", "The autosave directory (default "+view.DefaultAutosaveDir()+")")
+	flaggy.String(&eo.demo, "", "demo", "Settle with the demo field, the seed and the density are used ["+strings.Join(demoNames, "|")+"]")
+	flaggy.String(&eo.layout, "l", "layout", "Settle with the layout file of \"template x y\" lines")
+	flaggy.Bool(&eo.merge, "", "merge", "Add the layout and the --rle pattern to the random or seeded field instead of replacing it")
 	flaggy.String(&eo.engine, "e", "engine", "Engine to use ["+strings.Join(engineNames, "|")+"]")
+	flaggy.Int(&vo.MaxFPS, "", "fps", "Limit the console UI redrawing to fps frames per second, 0 - redraw on every step")
+	flaggy.Int(&vo.MaxRenderCells, "", "maxRenderCells", "Redraw the fields with more cells once a second to keep the console UI responsive, 0 - no limit")
+	flaggy.String(&vo.CropMessage, "", "cropMessage", "The message shown when the field is larger than the viewing area")
+	flaggy.String(&vo.CropGlyph, "", "cropGlyph", "The crop indicator for the panes narrower than cropMessage")
+	flaggy.String(&vo.CropColor, "", "cropColor", "The crop indicator color ["+strings.Join(view.ColorNames, "|")+"] (default "+view.DefCropColor+")")
+	flaggy.String(&vo.CropAnchor, "", "cropAnchor", "The part of the field shown when it is larger than the viewing area ["+strings.Join(view.CropAnchors, "|")+"]")
+	flaggy.String(&vo.HeaderText, "", "title", "The header text of the console UI (default "+strconv.Quote(view.DefHeaderText)+")")
+	flaggy.String(&vo.HeaderColor, "", "headerColor", "The header text color ["+strings.Join(view.ColorNames, "|")+"] (default "+view.DefHeaderColor+")")
+	flaggy.String(&vo.HeaderBgColor, "", "headerBgColor", "The header background color ["+strings.Join(view.ColorNames, "|")+"] (default "+view.DefHeaderBgColor+")")
+	flaggy.String(&vo.PrefsFile, "", "prefs", "The UI preferences file, \"none\" to not keep the preferences (default "+view.DefaultPrefsFile()+")")
+	flaggy.Bool(&vo.SaveViewport, "", "saveViewport", "Keep the cursor and the pan in the UI preferences file, they are restored for the field of the same size")
+	flaggy.Int(&eo.clusters, "", "connectivity", "The neighbours of the live cells clusters counted by the C key: 4 - orthogonal, 8 - with the diagonal ones")
+	flaggy.String(&eo.status, "", "status", "The comma separated status panel metrics in the display order ["+strings.Join(view.StatusMetrics, "|")+"] (default "+strings.Join(view.DefStatusMetrics, ",")+")")
+	flaggy.String(&eo.glyphs, "", "stateGlyphs", "The comma separated state=glyph:color glyphs of all the live cells states of the multi-color rule, for example 1=#:green,2=@:red,3=%:blue,4=*:yellow")
+	flaggy.String(&eo.speeds, "", "speeds", "The comma separated name=interval speed presets of the number keys, for example slow=1s,fast=10ms (default slow=500ms,normal=100ms,fast=20ms,turbo=0s)")
+	flaggy.String(&vo.ImageLabel, "", "imageLabel", "The corner of the generation label drawn on the image copied by Shift+X, switched by Shift+G ["+strings.Join(universe.LabelCorners, "|")+"] (default none)")
+	flaggy.Bool(&vo.ImageLabelPopulation, "", "imageLabelPopulation", "Show the population in the image label too")
+	flaggy.Bool(&vo.Summary, "", "summary", "Print the JSON summary only when the simulation is finished (run mode)")
+	flaggy.Bool(&eo.noBell, "", "no-bell", "Do not ring the terminal bell when the simulation is finished")
+	flaggy.Bool(&eo.printKeys, "", "print-keys", "Print the console UI key bindings table and exit")
+	flaggy.Bool(&eo.printCaps, "", "print-capabilities", "Print the capabilities JSON sent to the clients attached to --serve and exit")
+	flaggy.String(&eo.serve, "", "serve", "Serve the simulation on the Unix socket, the console UI is attached to it by the \"attach\" mode")
+	flaggy.String(&eo.logFile, "", "log-file", "Append the significant events (start, stop, finish with the reason and the peak population) to the file as JSON lines")
 
+	width, height := uo.Width, uo.Height
 	flaggy.Parse()
+	eo.sized = uo.Width != width || uo.Height != height
 
 	eo.interactive = uiMode.Used
-	if !uiMode.Used && !runMode.Used {
-		flaggy.ShowHelpAndExit("Specify the running mode \"run\" or \"ui\"")
+	switch vo.PrefsFile {
+	case "":
+		vo.PrefsFile = view.DefaultPrefsFile()
+	case "none":
+		vo.PrefsFile = ""
+	}
+	eo.sweep = sweepMode.Used
+	vo.Bell = !eo.noBell
+	uo.Advanced = make(map[string]interface{})
+	if eo.density != ruleDensity {
+		uo.Advanced[universe.AdvDensity] = eo.density
+	}
+	if eo.maxCells > 0 {
+		uo.Advanced[universe.AdvMaxCells] = eo.maxCells
+	}
+	if eo.maxPeriod > 0 {
+		uo.Advanced[universe.AdvMaxPeriod] = eo.maxPeriod
+	}
+	if eo.stepBudget > 0 {
+		uo.Advanced[universe.AdvStepBudget] = eo.stepBudget
+	}
+	if eo.history == -1 {
+		//only the console UI scrubs the history
+		eo.history = 0
+		if eo.interactive {
+			eo.history = universe.DefHistorySize
+		}
+	}
+	if eo.history < 0 {
+		flaggy.ShowHelpAndExit("the history can't be less than -1")
+	}
+	uo.Advanced[universe.AdvHistory] = eo.history
+	if eo.historyMB < 0 {
+		flaggy.ShowHelpAndExit("the historyMB can't be negative")
+	}
+	uo.Advanced[universe.AdvHistoryMB] = eo.historyMB
+	if eo.autostart {
+		uo.Advanced[universe.AdvAutostart] = true
+	}
+	if eo.rule != "" {
+		if _, err := universe.ParseRule(eo.rule); err != nil {
+			flaggy.ShowHelpAndExit(err.Error())
+		}
+		uo.Advanced[universe.AdvRule] = eo.rule
+	}
+	for _, rule := range eo.split {
+		if _, err := universe.ParseRule(rule); err != nil {
+			flaggy.ShowHelpAndExit(err.Error())
+		}
+	}
+	if eo.autosaveDir == "" {
+		eo.autosaveDir = view.DefaultAutosaveDir()
+	}
+	if eo.autosave < 0 || eo.autosaveEvery < 0 {
+		flaggy.ShowHelpAndExit("the autosave intervals can't be negative")
+	}
+	if eo.interactive && eo.rle == "" && eo.image == "" && eo.field == "" && (eo.autosave > 0 || eo.autosaveEvery > 0) {
+		if name := view.LatestAutosave(eo.autosaveDir); name != "" && confirm("Resume from the autosave "+name+"?") {
+			eo.rle = name
+			eo.resume = true
+		}
+	}
+	if eo.sortBy != "" && !contains(universe.SweepSorts, eo.sortBy) {
+		flaggy.ShowHelpAndExit("unknown sweep order")
+	}
+	if eo.rle != "" && eo.image != "" || (eo.rle != "" || eo.image != "") && eo.field != "" {
+		flaggy.ShowHelpAndExit("use one of --rle, --image or --field")
+	}
+	if eo.rle != "" {
+		eo.pattern = loadRLE(eo, uo)
+		eo.patternPath = eo.rle
+	}
+	if eo.image != "" {
+		if eo.threshold < 0 || eo.threshold > 255 {
+			flaggy.ShowHelpAndExit("the threshold should be in range [0, 255]")
+		}
+		eo.pattern = loadImage(eo.image, uint8(eo.threshold))
+		eo.patternPath = eo.image
+		uo.Advanced[universe.AdvPattern] = eo.pattern.Name
+	}
+	if eo.field != "" {
+		a, err := universe.DecodeArea(eo.field)
+		if err != nil {
+			flaggy.ShowHelpAndExit(err.Error())
+		}
+		eo.pattern = areaTemplate("field", a)
+	}
+	if eo.boundary != "" {
+		if _, ok := universe.ParseBoundary(eo.boundary); !ok {
+			flaggy.ShowHelpAndExit("unknown boundary")
+		}
+		uo.Advanced[universe.AdvBoundary] = eo.boundary
+	}
+	if eo.demo != "" {
+		d, ok := demos[eo.demo]
+		if !ok {
+			flaggy.ShowHelpAndExit("unknown demo")
+		}
+		if eo.boundary == "" {
+			uo.Advanced[universe.AdvBoundary] = string(d.boundary)
+		}
+	}
+	if eo.origin != "" {
+		if eo.origin != universe.OriginTopLeft && eo.origin != universe.OriginBottomLeft {
+			flaggy.ShowHelpAndExit("unknown origin")
+		}
+		uo.Advanced[universe.AdvOrigin] = eo.origin
+	}
+	if eo.onMaxSteps != "" {
+		if !contains(onMaxStepsValues, eo.onMaxSteps) {
+			flaggy.ShowHelpAndExit("unknown onMaxSteps value")
+		}
+		uo.Advanced[universe.AdvOnMaxSteps] = eo.onMaxSteps
+	}
+	if vo.CropColor != "" && !contains(view.ColorNames, vo.CropColor) {
+		flaggy.ShowHelpAndExit("unknown crop color")
+	}
+	if vo.CropAnchor != "" && !contains(view.CropAnchors, vo.CropAnchor) {
+		flaggy.ShowHelpAndExit("unknown crop anchor")
+	}
+	if vo.ImageLabel != "" && !contains(universe.LabelCorners, vo.ImageLabel) {
+		flaggy.ShowHelpAndExit("unknown image label corner")
+	}
+	if vo.HeaderColor != "" && !contains(view.ColorNames, vo.HeaderColor) || vo.HeaderBgColor != "" && !contains(view.ColorNames, vo.HeaderBgColor) {
+		flaggy.ShowHelpAndExit("unknown header color")
+	}
+	if eo.anchor != "" && !contains(anchorNames, eo.anchor) {
+		flaggy.ShowHelpAndExit("unknown anchor")
+	}
+	vo.PatternAnchor = universe.Anchor(eo.anchor)
+	if eo.status != "" {
+		metrics, err := view.ParseStatusMetrics(eo.status)
+		if err != nil {
+			flaggy.ShowHelpAndExit(err.Error())
+		}
+		vo.StatusMetrics = metrics
+	}
+	if c := universe.Connectivity(eo.clusters); c != universe.Connectivity4 && c != universe.Connectivity8 {
+		flaggy.ShowHelpAndExit("the connectivity should be 4 or 8")
+	}
+	vo.Connectivity = universe.Connectivity(eo.clusters)
+	if eo.speeds != "" {
+		presets, err := view.ParseSpeedPresets(eo.speeds)
+		if err != nil {
+			flaggy.ShowHelpAndExit(err.Error())
+		}
+		vo.SpeedPresets = presets
+	}
+	if eo.glyphs != "" {
+		glyphs, err := view.ParseStateGlyphs(eo.glyphs)
+		if err != nil {
+			flaggy.ShowHelpAndExit(err.Error())
+		}
+		rules := eo.split
+		if rule, ok := uo.Advanced[universe.AdvRule].(string); ok {
+			rules = append([]string{rule}, rules...)
+		}
+		for _, rule := range rules {
+			//the rules are validated above
+			if r, err := universe.ParseRule(rule); err == nil && r.Colors > 0 {
+				if err := view.ValidateStateGlyphs(glyphs, r); err != nil {
+					flaggy.ShowHelpAndExit(err.Error())
+				}
+			}
+		}
+		vo.StateGlyphs = glyphs
+	}
+	if eo.printCaps {
+		if err := json.NewEncoder(os.Stdout).Encode(capabilities(eo.engine)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if eo.printKeys {
+		if err := view.PrintKeys(os.Stdout); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if attachMode.Used && eo.attach == "" {
+		flaggy.ShowHelpAndExit("Specify the --socket of the served simulation")
+	}
+	if !uiMode.Used && !runMode.Used && !sweepMode.Used && !attachMode.Used && eo.bench <= 0 && !eo.lifespan {
+		flaggy.ShowHelpAndExit("Specify the running mode \"run\", \"ui\", \"sweep\" or \"attach\"")
 	}
 
 	_, ok := engines[eo.engine]
@@ -127,3 +550,274 @@ func initOptions() (eo *EnvOptions, uo *universe.Options) {
 
 	return
 }
+
+//newUniverse creates the universe by the engine option and settles it
+//shows the usage and exits if the options are invalid
+func newUniverse(eo *EnvOptions, uo *universe.Options, stateCh chan universe.Status) universe.Universe {
+	u, err := engines[eo.engine](uo, stateCh)
+	if err != nil {
+		flaggy.ShowHelpAndExit(err.Error())
+	}
+
+	u.AddTemplate(
+		universe.Template{
+			Name:        "testSample1",
+			Descr:       "the test sample with 3 stable patterns",
+			Coordinates: testSample,
+		})
+
+	if d, ok := demos[eo.demo]; ok {
+		d.settle(u, eo)
+		return u
+	}
+	if eo.seed != 0 {
+		settleSeed(u, eo.seed)
+	} else if eo.randomData {
+		u.SettleWithRandomData()
+	} else if eo.layout == "" && eo.pattern == nil {
+		u.SettleTemplate("testSample1")
+	}
+	//the layout and the pattern replace the field settled before unless merge is set
+	if eo.layout != "" {
+		settleLayout(u, eo.layout, eo.merge)
+		u.SetSource(universe.Source{Name: filepath.Base(eo.layout), Path: eo.layout})
+	}
+	if eo.pattern != nil {
+		u.AddTemplate(*eo.pattern)
+		printWarnings(u.SettleLayout([]universe.Placement{{Name: eo.pattern.Name, X: eo.patternX, Y: eo.patternY, Anchor: universe.Anchor(eo.anchor)}}, eo.merge))
+		u.SetSource(universe.Source{Name: eo.pattern.Name, Path: eo.patternPath})
+	}
+	return u
+}
+
+//runSweep runs the seeds sweep and prints the results sorted by the metric with the initial fields encoding
+func runSweep(uo *universe.Options, seeds int, sortBy string) {
+	fmt.Printf("Running %v seeds on %v x %v field up to %v steps...\n", seeds, uo.Width, uo.Height, uo.MaxSteps)
+	results := universe.SweepSeeds(uo, seeds, uo.MaxSteps)
+	universe.SortSweepResults(results, sortBy)
+	fmt.Printf("%10s %12s %10s %10s %10s %8s  %-22s %s\n", "Seed", "Generations", "Peak", "Peak step", "Final", "Period", "Reason", "Field (see --field)")
+	for _, r := range results {
+		fmt.Printf("%10v %12v %10v %10v %10v %8v  %-22s %s\n", r.Seed, r.Generations, r.PeakPopulation, r.PeakGeneration, r.FinalPopulation, r.Period, r.FinishReason, r.Encoding)
+	}
+}
+
+//runBench runs the random area of the seed on each engine, prints the speed and exits with 1 if any final field differs
+func runBench(uo *universe.Options, seed int64, steps int) {
+	if seed == 0 {
+		seed = 1
+	}
+	fmt.Printf("Running seed %v on %v x %v field for %v steps on each engine...\n", seed, uo.Width, uo.Height, steps)
+	results, err := universe.BenchEngines(engines, uo, seed, steps)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("%-14s %12s %12s %10s  %s\n", "Engine", "Duration", "Steps/sec", "Final", "Field")
+	failed := false
+	for _, r := range results {
+		field := "matches"
+		if r.Differs != 0 {
+			field = fmt.Sprintf("differs in %v cells", r.Differs)
+			failed = true
+		}
+		fmt.Printf("%-14s %12v %12.1f %10v  %s\n", r.Engine, r.Duration.Round(time.Millisecond), r.StepsPerSec, r.FinalPopulation, field)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+//runLifespan analyzes the field of the universe until it stabilizes and prints the lifespan
+func runLifespan(u universe.Universe, maxSteps int, asJSON bool) {
+	l := universe.AnalyzeLifespan(u.Area(), u.Rule(), u.Boundary(), maxSteps)
+	u.Close()
+	if err := view.PrintLifespan(os.Stdout, l, asJSON); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+//capabilities returns the capabilities of the build serving the universe on the engine
+func capabilities(engine string) view.Capabilities {
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	return view.NewCapabilities(engine, names)
+}
+
+//runAttached shows the simulation served on the socket until the UI is detached
+func runAttached(socket string) {
+	a, err := view.Attach(socket)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	a.Start()
+	if a.Err() != nil {
+		fmt.Println(a.Err())
+		os.Exit(1)
+	}
+}
+
+//loadRLE loads the pattern from the RLE file, its name and rule (unless the rule is set) are stored to the options
+//the boundary and the field size of the bounded grid are applied unless they are set by the flags
+//the resumed pattern is placed by its #R position
+func loadRLE(eo *EnvOptions, uo *universe.Options) *universe.Template {
+	name := eo.rle
+	f, err := os.Open(name)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	p, warnings, err := universe.LoadRLE(f)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	printWarnings(warnings)
+	if p.Name == "" {
+		p.Name = filepath.Base(name)
+	}
+	uo.Advanced[universe.AdvPattern] = p.Name
+	if _, ok := uo.Advanced[universe.AdvRule]; !ok && p.Rule != "" {
+		if _, err := universe.ParseRule(p.Rule); err != nil {
+			fmt.Println("warning: the pattern rule", p.Rule, "is not supported")
+		} else {
+			uo.Advanced[universe.AdvRule] = p.Rule
+		}
+	}
+	if p.Boundary != "" && eo.boundary == "" {
+		uo.Advanced[universe.AdvBoundary] = string(p.Boundary)
+	}
+	if !eo.sized {
+		if p.FieldWidth > 0 {
+			uo.Width = p.FieldWidth
+		}
+		if p.FieldHeight > 0 {
+			uo.Height = p.FieldHeight
+		}
+	}
+	if eo.resume {
+		//the saved position is the corner of the pattern
+		eo.anchor = string(universe.AnchorTopLeft)
+		eo.patternX, eo.patternY = p.X, p.Y
+		if eo.origin == universe.OriginBottomLeft {
+			eo.patternY = uo.Height - p.Y - p.Height
+		}
+	}
+	tmpl := p.Template()
+	return &tmpl
+}
+
+//loadImage loads the pattern from the image file named by the file
+func loadImage(name string, threshold uint8) *universe.Template {
+	f, err := os.Open(name)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	a, err := universe.LoadImage(f, threshold)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	return areaTemplate(filepath.Base(name), a)
+}
+
+//areaTemplate returns the template of the live cells of the area
+func areaTemplate(name string, a universe.Area) *universe.Template {
+	tmpl := universe.Template{Name: name, Coordinates: make([][]int, 0)}
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			if a.Entities[y][x] {
+				tmpl.Coordinates = append(tmpl.Coordinates, []int{x, y})
+			}
+		}
+	}
+	return &tmpl
+}
+
+//confirm asks the yes/no question on the console, no is the default
+func confirm(question string) bool {
+	fmt.Print(question, " [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+//settleSeed settles the universe with the random area of the seed
+func settleSeed(u universe.Universe, seed int64) {
+	o := u.Options()
+	a := universe.RandomArea(o.Width, o.Height, seedDensity(u), seed)
+	points := make([]universe.Point, 0)
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			if a.Entities[y][x] {
+				points = append(points, universe.Point{X: x, Y: y})
+			}
+		}
+	}
+	u.SetCells(points, true)
+}
+
+//seedDensity returns the share of the live cells of the random seeding, the suggested density of the rule unless it's set
+func seedDensity(u universe.Universe) float64 {
+	if d, ok := u.Options().Advanced[universe.AdvDensity].(float64); ok {
+		return d
+	}
+	return universe.SuggestedDensity(u.Rule())
+}
+
+//settleGliderFleet settles the universe with the glider fleet by the seed and the density
+func settleGliderFleet(u universe.Universe, eo *EnvOptions) {
+	seed := eo.seed
+	if seed == 0 {
+		seed = 1
+	}
+	o := u.Options()
+	//the density of the fleet is the share of the occupied slots, the cells density of the rule doesn't apply
+	density := eo.density
+	if density == ruleDensity {
+		density = universe.DefDensity
+	}
+	fleet := universe.GliderFleet(o.Width, o.Height, density, seed)
+	u.AddTemplate(fleet)
+	printWarnings(u.SettleLayout([]universe.Placement{{Name: fleet.Name}}, eo.merge))
+	u.SetSource(universe.Source{Name: fleet.Name})
+}
+
+//settleLayout settles the universe with the layout file and prints the warnings
+func settleLayout(u universe.Universe, name string, merge bool) {
+	f, err := os.Open(name)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	placements, err := universe.LoadLayout(f)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	printWarnings(u.SettleLayout(placements, merge))
+}
+
+//printWarnings prints the warnings one per line
+func printWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Println("warning:", w)
+	}
+}
+
+//contains checks if the values contains v
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package universe
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"unsafe"
+)
+
+//Point represents the cell coordinates in the area
+type Point struct {
+	X int
+	Y int
+}
+
+var (
+	ErrAreaSizeMismatch = errors.New("the areas have different dimensions")
+)
+
+//Diff returns the coordinates of the cells which states differ in a and b, the points are in row-major order
+//returns ErrAreaSizeMismatch if the areas have different dimensions
+func Diff(a Area, b Area) ([]Point, error) {
+	if a.Width != b.Width || a.Height != b.Height {
+		return nil, ErrAreaSizeMismatch
+	}
+	points := make([]Point, 0)
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			if a.Entities[y][x] != b.Entities[y][x] {
+				points = append(points, Point{x, y})
+			}
+		}
+	}
+	return points, nil
+}
+
+//copyArea returns the deep copy of the area
+func copyArea(a Area) Area {
+	c := createArea(a.Width, a.Height)
+	for y := range a.Entities {
+		copy(c.Entities[y], a.Entities[y])
+	}
+	if a.Colors != nil {
+		c.Colors = createColors(a.Width, a.Height)
+		for y := range a.Colors {
+			copy(c.Colors[y], a.Colors[y])
+		}
+	}
+	return c
+}
+
+//nextGeneration calculates the next generation of the area by the rule and the boundary, the area itself is not changed
+func nextGeneration(a Area, r Rule, b Boundary) Area {
+	next := createArea(a.Width, a.Height)
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			next.Entities[y][x] = Cell(r.nextState(bool(a.Entities[y][x]), liveNeighbours(a, x, y, b.WrapX(), b.WrapY())))
+		}
+	}
+	return next
+}
+
+//LiveBounds returns the top left and the bottom right corners of the live cells bounding box, ok is false if there are no live cells
+func LiveBounds(a Area) (min Point, max Point, ok bool) {
+	for y := range a.Entities {
+		for x, c := range a.Entities[y] {
+			if !c {
+				continue
+			}
+			if !ok {
+				min, max, ok = Point{x, y}, Point{x, y}, true
+				continue
+			}
+			if x < min.X {
+				min.X = x
+			}
+			if x > max.X {
+				max.X = x
+			}
+			max.Y = y
+		}
+	}
+	return
+}
+
+//Trim returns the minimal sub-area containing all the live cells, its origin in the area is LiveBounds min
+//the empty area is trimmed to 0x0
+func Trim(a Area) Area {
+	min, max, ok := LiveBounds(a)
+	if !ok {
+		return createArea(0, 0)
+	}
+	t := createArea(max.X-min.X+1, max.Y-min.Y+1)
+	if a.Colors != nil {
+		t.Colors = createColors(t.Width, t.Height)
+	}
+	for y := range t.Entities {
+		copy(t.Entities[y], a.Entities[min.Y+y][min.X:max.X+1])
+		if a.Colors != nil {
+			copy(t.Colors[y], a.Colors[min.Y+y][min.X:max.X+1])
+		}
+	}
+	return t
+}
+
+//CellForecast returns the live neighbours count of the cell at x, y and its state after the next step by the rule and the boundary
+func CellForecast(a Area, r Rule, b Boundary, x int, y int) (neighbours int, next bool) {
+	neighbours = liveNeighbours(a, x, y, b.WrapX(), b.WrapY())
+	return neighbours, r.nextState(bool(a.Entities[y][x]), neighbours)
+}
+
+//NeighbourCounts is the count of the live and the dead cells by their live neighbours count, indexed by 0..8
+type NeighbourCounts struct {
+	Live [9]int
+	Dead [9]int
+}
+
+//CountNeighbours counts the cells of the area by their live neighbours count in a single pass by the boundary
+//compared with the rule it shows which live cells survive and which dead ones are born
+func CountNeighbours(a Area, b Boundary) (c NeighbourCounts) {
+	for y := range a.Entities {
+		for x, e := range a.Entities[y] {
+			n := liveNeighbours(a, x, y, b.WrapX(), b.WrapY())
+			if e {
+				c.Live[n]++
+			} else {
+				c.Dead[n]++
+			}
+		}
+	}
+	return
+}
+
+//Forecast returns the cells which are born and die on the next step of the area by the rule and the boundary
+//the area itself is not changed, the points are in row-major order
+func Forecast(a Area, r Rule, b Boundary) (born []Point, died []Point) {
+	next := nextGeneration(a, r, b)
+	//the areas have the same dimensions
+	changed, _ := Diff(a, next)
+	for _, p := range changed {
+		if next.Entities[p.Y][p.X] {
+			born = append(born, p)
+		} else {
+			died = append(died, p)
+		}
+	}
+	return
+}
+
+//Centroid returns the mean position of the live cells, ok is false if there are no live cells
+//the circular mean is used on the wrapped axes, so the pattern straddling the seam is centred between its parts
+//the linear mean is used on the fixed axes and when the live cells are spread evenly around the wrapped axis
+func Centroid(a Area, b Boundary) (x float64, y float64, ok bool) {
+	n := 0
+	var sumX, sumY, cosX, sinX, cosY, sinY float64
+	for cy := range a.Entities {
+		for cx := range a.Entities[cy] {
+			if !a.Entities[cy][cx] {
+				continue
+			}
+			n++
+			sumX += float64(cx)
+			sumY += float64(cy)
+			ax, ay := 2*math.Pi*float64(cx)/float64(a.Width), 2*math.Pi*float64(cy)/float64(a.Height)
+			cosX, sinX = cosX+math.Cos(ax), sinX+math.Sin(ax)
+			cosY, sinY = cosY+math.Cos(ay), sinY+math.Sin(ay)
+		}
+	}
+	if n == 0 {
+		return 0, 0, false
+	}
+	x, y = sumX/float64(n), sumY/float64(n)
+	if b.WrapX() {
+		x = circularMean(cosX, sinX, n, a.Width, x)
+	}
+	if b.WrapY() {
+		y = circularMean(cosY, sinY, n, a.Height, y)
+	}
+	return x, y, true
+}
+
+//Velocity returns the centroid shift per step between the areas taken steps apart, ok is false if any area is empty
+//the shift along the wrapped axes is taken the shortest way around
+func Velocity(from Area, to Area, b Boundary, steps int) (vx float64, vy float64, ok bool) {
+	x1, y1, ok1 := Centroid(from, b)
+	x2, y2, ok2 := Centroid(to, b)
+	if !ok1 || !ok2 || steps <= 0 {
+		return 0, 0, false
+	}
+	dx, dy := x2-x1, y2-y1
+	if b.WrapX() {
+		dx -= float64(from.Width) * math.Round(dx/float64(from.Width))
+	}
+	if b.WrapY() {
+		dy -= float64(from.Height) * math.Round(dy/float64(from.Height))
+	}
+	return dx / float64(steps), dy / float64(steps), true
+}
+
+//circularMean returns the position on the wrapped axis of the size by the sums of the cells angles cosines and sines
+//linear is returned if the mean direction is undefined
+func circularMean(cos float64, sin float64, n int, size int, linear float64) float64 {
+	if math.Hypot(cos, sin) < 1e-9*float64(n) {
+		return linear
+	}
+	angle := math.Atan2(sin, cos)
+	if angle < 0 {
+		angle += 2 * math.Pi
+	}
+	return angle * float64(size) / (2 * math.Pi)
+}
+
+//areaBytes returns the bytes taken by the area cells and rows
+func areaBytes(a Area) int {
+	bytes := a.Width*a.Height*int(unsafe.Sizeof(Cell(false))) + len(a.Entities)*int(unsafe.Sizeof(a.Entities))
+	if a.Colors != nil {
+		bytes += a.Width*a.Height + len(a.Colors)*int(unsafe.Sizeof(a.Colors))
+	}
+	return bytes
+}
+
+//areaHash returns the hash of the cells states and colors, the equal areas have the equal hashes
+func areaHash(a Area) uint64 {
+	h := fnv.New64a()
+	row := make([]byte, a.Width)
+	for y := range a.Entities {
+		for x, c := range a.Entities[y] {
+			row[x] = 0
+			if c {
+				row[x] = 1
+				if a.Colors != nil {
+					row[x] = a.Colors[y][x]
+				}
+			}
+		}
+		_, _ = h.Write(row)
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,178 @@
+package universe
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+//newTestArea creates the area with live cells at coordinates vc
+func newTestArea(width int, height int, vc [][]int) Area {
+	a := createArea(width, height)
+	for _, c := range vc {
+		a.Entities[c[1]][c[0]] = true
+	}
+	return a
+}
+
+func TestDiff(t *testing.T) {
+	a := newTestArea(4, 3, [][]int{{0, 0}, {1, 1}, {3, 2}})
+	b := newTestArea(4, 3, [][]int{{1, 1}, {2, 1}, {3, 2}})
+	got, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Point{{0, 0}, {2, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+
+	if got, _ := Diff(a, a); len(got) != 0 {
+		t.Errorf("Diff() of the same areas = %v, want none", got)
+	}
+
+	if _, err := Diff(a, createArea(3, 4)); err != ErrAreaSizeMismatch {
+		t.Errorf("Diff() of mismatched areas error = %v, want %v", err, ErrAreaSizeMismatch)
+	}
+}
+
+func TestCellForecast(t *testing.T) {
+	//the horizontal blinker on the top edge
+	a := newTestArea(5, 5, [][]int{{1, 0}, {2, 0}, {3, 0}})
+	tests := []struct {
+		name           string
+		x, y           int
+		b              Boundary
+		wantNeighbours int
+		wantNext       bool
+	}{
+		{"centre survives", 2, 0, BoundaryNone, 2, true},
+		{"end dies", 1, 0, BoundaryNone, 1, false},
+		{"below is born", 2, 1, BoundaryNone, 3, true},
+		{"wrapped above is born", 2, 4, BoundaryWrapY, 3, true},
+		{"not wrapped above stays dead", 2, 4, BoundaryNone, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, next := CellForecast(a, ConwayRule, tt.b, tt.x, tt.y)
+			if n != tt.wantNeighbours || next != tt.wantNext {
+				t.Errorf("got %d, %v, want %d, %v", n, next, tt.wantNeighbours, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestCountNeighbours(t *testing.T) {
+	//the horizontal blinker filling the middle row
+	a := newTestArea(3, 3, [][]int{{0, 1}, {1, 1}, {2, 1}})
+	tests := []struct {
+		name string
+		b    Boundary
+		want NeighbourCounts
+	}{
+		{"none", BoundaryNone, NeighbourCounts{Live: [9]int{1: 2, 2: 1}, Dead: [9]int{2: 4, 3: 2}}},
+		{"torus", BoundaryTorus, NeighbourCounts{Live: [9]int{2: 3}, Dead: [9]int{3: 6}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountNeighbours(a, tt.b); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForecast(t *testing.T) {
+	//the vertical blinker turns horizontal
+	a := newTestArea(5, 5, [][]int{{2, 1}, {2, 2}, {2, 3}})
+	born, died := Forecast(a, ConwayRule, BoundaryNone)
+	if want := []Point{{1, 2}, {3, 2}}; !reflect.DeepEqual(born, want) {
+		t.Errorf("born = %v, want %v", born, want)
+	}
+	if want := []Point{{2, 1}, {2, 3}}; !reflect.DeepEqual(died, want) {
+		t.Errorf("died = %v, want %v", died, want)
+	}
+	if !a.Entities[1][2] || a.Entities[2][1] {
+		t.Error("the area is changed")
+	}
+
+	if born, died := Forecast(newTestArea(4, 4, [][]int{{1, 1}, {2, 1}, {1, 2}, {2, 2}}), ConwayRule, BoundaryNone); born != nil || died != nil {
+		t.Errorf("got the changes of the still life %v, %v, want none", born, died)
+	}
+}
+
+func TestVelocity(t *testing.T) {
+	glider := [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	tests := []struct {
+		name string
+		b    Boundary
+		x, y int
+	}{
+		{"fixed edges", BoundaryNone, 1, 1},
+		//the glider crosses the bottom right corner
+		{"torus", BoundaryTorus, 7, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vc := make([][]int, 0, len(glider))
+			for _, c := range glider {
+				vc = append(vc, []int{c[0] + tt.x, c[1] + tt.y})
+			}
+			a := newTestArea(12, 12, vc)
+			//the glider moves by 1, 1 each 4 generations
+			for i := 0; i < 4; i++ {
+				next := a
+				for g := 0; g < 4; g++ {
+					next = nextGeneration(next, ConwayRule, tt.b)
+				}
+				vx, vy, ok := Velocity(a, next, tt.b, 4)
+				if !ok || math.Abs(vx-0.25) > 1e-9 || math.Abs(vy-0.25) > 1e-9 {
+					t.Errorf("period %d: got velocity %v, %v, want 0.25, 0.25", i, vx, vy)
+				}
+				a = next
+			}
+		})
+	}
+
+	//the parts of the block on the opposite edges are centred on the seam
+	a := newTestArea(10, 10, [][]int{{9, 4}, {9, 5}, {0, 4}, {0, 5}})
+	if x, y, _ := Centroid(a, BoundaryWrapX); math.Abs(x-9.5) > 1e-9 || math.Abs(y-4.5) > 1e-9 {
+		t.Errorf("got centroid %v, %v, want 9.5, 4.5", x, y)
+	}
+	if _, _, ok := Centroid(createArea(10, 10), BoundaryTorus); ok {
+		t.Error("got the centroid of the empty area")
+	}
+}
+
+func TestTrim(t *testing.T) {
+	tests := []struct {
+		name       string
+		a          Area
+		want       Area
+		wantOrigin Point
+	}{
+		{"empty", createArea(4, 3), createArea(0, 0), Point{}},
+		{"corner", newTestArea(5, 4, [][]int{{3, 2}, {4, 3}}), newTestArea(2, 2, [][]int{{0, 0}, {1, 1}}), Point{3, 2}},
+		{"full field", newTestArea(3, 2, [][]int{{0, 0}, {2, 1}}), newTestArea(3, 2, [][]int{{0, 0}, {2, 1}}), Point{0, 0}},
+		{"glider", newTestArea(6, 6, [][]int{{2, 1}, {3, 2}, {1, 3}, {2, 3}, {3, 3}}),
+			newTestArea(3, 3, [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}), Point{1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Trim(tt.a)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Trim() = %v, want %v", got, tt.want)
+			}
+			if origin, _, _ := LiveBounds(tt.a); origin != tt.wantOrigin {
+				t.Errorf("LiveBounds() origin = %v, want %v", origin, tt.wantOrigin)
+			}
+		})
+	}
+
+	a := newTestArea(4, 4, [][]int{{1, 2}})
+	a.Colors = createColors(4, 4)
+	a.Colors[2][1] = 7
+	if got := Trim(a); len(got.Colors) != 1 || got.Colors[0][0] != 7 {
+		t.Errorf("Trim() colors = %v, want [[7]]", got.Colors)
+	}
+}
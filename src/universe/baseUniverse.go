@@ -1,8 +1,10 @@
 package universe
 
 import (
+	"fmt"
 	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,6 +14,7 @@ type Area struct {
 	Width    int
 	Height   int
 	Entities [][]Cell
+	Colors   [][]uint8 //the colors 1..Rule.Colors of the live cells for the multi-color rules, nil for the two-state rules
 }
 
 //Options represents the Universe's configurable options
@@ -26,11 +29,20 @@ type Options struct {
 
 //Status represents the status of the Universe at concrete moment
 type Status struct {
-	IterationNum  int
-	RunningMode   RunningState
-	LiveCells     int
-	IterationTime time.Duration
-	Details       map[string]interface{} //advanced details (engine specific)
+	IterationNum     int
+	RunningMode      RunningState
+	LiveCells        int
+	IterationTime    time.Duration
+	AvgIterationTime time.Duration          //exponential moving average of IterationTime over the last DefAvgIterations steps
+	StepHistogram    StepHistogram          //the IterationTime of the last DefHistogramSteps steps counted by StepHistogramBounds
+	PublishTime      time.Duration          //the time of the area snapshot publishing after the last step (buffer swap)
+	Memory           int                    //the estimated bytes of the engine field representation and the generations history after the last step
+	HistoryMemory    int                    //the bytes of the generations history kept for scrubbing, counted in Memory too
+	FinishReason     FinishReason           //why the simulation is finished
+	Period           int                    //the period of the cycle which finished the simulation, 0 if none
+	Components       []int                  //live cells clusters sizes calculated by CountComponents, nil if outdated
+	FastRun          bool                   //the views are not refreshed until the fast run is stopped
+	Details          map[string]interface{} //advanced details (engine specific)
 }
 
 //Viewer is the interface to any Viewer - the object who can display simulation data or control the engine
@@ -47,9 +59,18 @@ type Template struct {
 	Coordinates [][]int //array of [x,y] coordinates
 }
 
+//Source describes where the field is loaded from
+type Source struct {
+	Name string //the pattern or layout name
+	Path string //the file the pattern is loaded from, empty if it's not loaded from the file
+}
+
 //The universe running status at the concrete moment
 type RunningState int
 
+//FinishReason describes the condition which finished the simulation
+type FinishReason string
+
 //default options
 const (
 	DefSimulationInterval = time.Millisecond * 100
@@ -57,8 +78,52 @@ const (
 	DefWidth              = 40
 	DefHeight             = 15
 	DefMaxSkippedTicks    = 5
+	DefAvgIterations      = 10
+	DefModeChBuffer       = 8 //the running mode changes kept for the slow subscriber, the later ones are dropped
+)
+
+//advanced options names
+const (
+	AdvMaxCells   = "maxCells"   //the simulation is finished when the live cells count exceeds this value, 0 - unlimited
+	AdvMaxPeriod  = "maxPeriod"  //the simulation is finished when the area repeats within this number of steps, 0 - no cycle detection
+	AdvAutostart  = "autostart"  //the simulation is started right after the seeding
+	AdvOnMaxSteps = "onMaxSteps" //what to do when MaxSteps is reached, one of OnMaxSteps* values, stop by default
+	AdvRule       = "rule"       //the rule in the B/S notation, Conway's B3/S23 by default
+	AdvBoundary   = "boundary"   //the edges behaviour, one of Boundary values, none by default
+	AdvDensity    = "density"    //the share of the live cells in the random areas, SuggestedDensity of the rule by default
+	AdvPattern    = "pattern"    //the name of the loaded pattern, informational
+	AdvOrigin     = "origin"     //the origin of the layout and the displayed coordinates, one of Origin* values, top-left by default
+	AdvStepBudget = "stepBudget" //the running loop does the steps fitting this many milliseconds per tick (see StepWithin), 0 - one step per tick
+	AdvHistory    = "history"    //the count of the past generations kept for scrubbing (see Generation and Fork), 0 by default
+	AdvHistoryMB  = "historyMB"  //the megabytes the past generations kept for scrubbing take at most, DefHistoryMB by default, 0 - unlimited
+)
+
+//origin option values, the area is always stored top-down
+const (
+	OriginTopLeft    = "top-left"    //y increases downward (screen)
+	OriginBottomLeft = "bottom-left" //y increases upward (math)
 )
 
+//onMaxSteps option values
+const (
+	OnMaxStepsStop     = "stop"     //finish the simulation
+	OnMaxStepsContinue = "continue" //ignore the limit and keep going
+	OnMaxStepsReset    = "reset"    //restart from the initial seed
+)
+
+const (
+	FinishReasonNone      FinishReason = ""
+	FinishReasonMaxSteps  FinishReason = "max steps reached"
+	FinishReasonExtinct   FinishReason = "no live cells"
+	FinishReasonStable    FinishReason = "stable state reached"
+	FinishReasonCycle     FinishReason = "cycle detected"
+	FinishReasonCellLimit FinishReason = "cell limit reached"
+	FinishReasonSkipped   FinishReason = "too many skipped ticks"
+)
+
+//the finished universe is not a dead end: Run and FastRun switch it to RunningStateRun,
+//Step switches it through RunningStateStep to RunningStateManual (or back to finished if the finish condition still holds),
+//the manual edits of the field and Clear switch it to RunningStateManual
 const (
 	RunningStateManual   = 0x0
 	RunningStateStep     = 0x1
@@ -74,6 +139,12 @@ var DefaultUniverseOptions = Options{
 	MaxSkippedTicks: DefMaxSkippedTicks,
 }
 
+//areaSnapshot is the published area state with the area of the previous step
+type areaSnapshot struct {
+	area Area
+	prev Area
+}
+
 //BaseUniverse is the base universe's engine
 //implements Universe interface
 //can be used to create different implementations by redefining nextIteration func
@@ -87,21 +158,39 @@ type BaseUniverse struct {
 		Area
 		sync.Mutex
 	}
+	snapshot      atomic.Value //the areaSnapshot published after each change, read by Area()
 	stateCh       chan Status
+	modeChs       []chan RunningState //the running mode subscribers, guarded by the state lock
+	notifiedMode  RunningState        //the last mode sent to the subscribers, guarded by the state lock
+	source        Source              //where the field is loaded from, guarded by the state lock
+	steps         stepHistory         //the buckets of the StepHistogram steps, guarded by the state lock
 	views         []Viewer
+	quiet         int32             //views refreshing is suppressed if not 0, accessed atomically: refreshView is called by the other goroutines too
+	loopID        int               //the id of the active running cycle, the stale cycles exit when it's changed, guarded by the state lock
+	loopExit      func()            //the exit handler of the active running cycle, accessed by the main loop only
+	seed          Area              //the area before the first step, used to restart the simulation
+	history       []uint64          //the area hashes of the last steps for the cycle detection, guarded by the area lock
+	generations   generationHistory //the past generations kept for scrubbing and forking, guarded by the area lock
+	rule          Rule
+	boundary      Boundary
+	wrapX         bool //the boundary flags cached for the neighbours calculation
+	wrapY         bool
 	templates     map[string]Template
 	controlCh     chan func()
 	closeCh       chan bool
 	nextIteration func() (hasLiveEnitities bool, changed bool)
+	memoryUsage   func() int //estimates the bytes of the field representation
 }
 
 //NewBaseUniverse creates the BaseUniverse instance
-func NewBaseUniverse(o *Options, stateCh chan Status) *BaseUniverse {
+//returns ErrInvalidOptions if the dimensions are not positive
+func NewBaseUniverse(o *Options, stateCh chan Status) (*BaseUniverse, error) {
 	if o == nil {
 		o = &DefaultUniverseOptions
 	}
-	o.Advanced = make(map[string]interface{})
-	o.Advanced["engine"] = "base"
+	if o.Width <= 0 || o.Height <= 0 {
+		return nil, fmt.Errorf("%w: the dimensions should be positive, got %v x %v", ErrInvalidOptions, o.Width, o.Height)
+	}
 
 	u := BaseUniverse{
 		options:   *o,
@@ -110,14 +199,30 @@ func NewBaseUniverse(o *Options, stateCh chan Status) *BaseUniverse {
 		stateCh:   stateCh,
 		templates: map[string]Template{},
 	}
+	//the advanced options are copied to keep the caller's map untouched
+	u.options.Advanced = make(map[string]interface{}, len(o.Advanced)+1)
+	for k, v := range o.Advanced {
+		u.options.Advanced[k] = v
+	}
+	u.options.Advanced["engine"] = "base"
+	u.rule = ConwayRule
+	if r, err := ParseRule(u.advancedString(AdvRule, "")); err == nil {
+		u.rule = r
+	}
+	u.options.Advanced[AdvRule] = u.rule.String()
+	u.boundary, _ = ParseBoundary(u.advancedString(AdvBoundary, ""))
+	u.wrapX, u.wrapY = u.boundary.WrapX(), u.boundary.WrapY()
+	u.options.Advanced[AdvBoundary] = string(u.boundary)
 	//nextIteration can be implemented by successor
 	u.nextIteration = u._nextIteration
+	u.memoryUsage = u._memoryUsage
 	u.state.Details = make(map[string]interface{})
 
 	u.area.Area = createArea(o.Width, o.Height)
+	u.applyColors()
 	u.refreshView()
 	go u.mainLoop()
-	return &u
+	return &u, nil
 }
 
 //AddTemplate adds the seeding template to the internal storage
@@ -131,7 +236,9 @@ func (u *BaseUniverse) AddTemplate(tmpl Template) {
 func (u *BaseUniverse) Settle(vc [][]int) {
 	u.area.Lock()
 	u.settle(vc, Cell(true))
+	u.publishArea(Area{})
 	u.area.Unlock()
+	u.reactivate()
 	u.refreshView()
 }
 
@@ -143,25 +250,50 @@ func (u *BaseUniverse) SettleTemplate(name string) {
 	}
 	u.area.Lock()
 	u.settle(tmpl.Coordinates, Cell(true))
+	u.publishArea(Area{})
 	u.area.Unlock()
-	u.state.LiveCells = u.liveCells()
+	u.setLiveCells(u.liveCells())
+	u.reactivate()
 	u.refreshView()
 }
 
 //SettleWithRandomData populates the universe with random data
+//the share of the live cells is the density option, the suggested density of the rule unless it's set
+//returns when the field is settled
 func (u *BaseUniverse) SettleWithRandomData() {
-	if u.state.RunningMode == RunningStateManual || u.state.RunningMode == RunningStateFinished {
+	if mode := u.Status().RunningMode; mode == RunningStateManual || mode == RunningStateFinished {
+		done := make(chan bool)
 		u.controlCh <- u.clear
 		u.controlCh <- func() {
+			density := u.density()
 			u.area.Lock()
-			for i := 0; i < u.area.Width*u.area.Height; i++ {
-				u.settle([][]int{{rand.Intn(u.area.Width), rand.Intn(u.area.Height)}}, Cell(true))
-			}
+			u.walkArea(func(x int, y int, _ Cell) {
+				if rand.Float64() >= density {
+					return
+				}
+				color := uint8(1)
+				if u.rule.Colors > 0 {
+					color = uint8(rand.Intn(u.rule.Colors) + 1)
+				}
+				u.setCell(x, y, true, color)
+			})
+			u.publishArea(Area{})
 			u.area.Unlock()
-			u.state.LiveCells = u.liveCells()
+			u.setLiveCells(u.liveCells())
 			u.refreshView()
+			done <- true
 		}
+		<-done
+	}
+}
+
+//density returns the share of the live cells in the random areas, the suggested density of the active rule if it's not set
+//should be called by the main loop
+func (u *BaseUniverse) density() float64 {
+	if d, ok := u.options.Advanced[AdvDensity].(float64); ok {
+		return d
 	}
+	return SuggestedDensity(u.rule)
 }
 
 //InverseCell inverses the cell state at point x, y
@@ -170,11 +302,55 @@ func (u *BaseUniverse) InverseCell(x int, y int) {
 		return
 	}
 	u.area.Lock()
-	u.area.Entities[y][x] = !u.area.Entities[y][x]
+	u.setCell(x, y, !bool(u.area.Entities[y][x]), 1)
+	u.publishArea(Area{})
+	u.area.Unlock()
+	u.clearSource()
+	u.reactivate()
+	u.refreshView()
+}
+
+//SetCell sets the cell state at point x, y, the points outside the area are ignored
+func (u *BaseUniverse) SetCell(x int, y int, live bool) {
+	u.SetCells([]Point{{x, y}}, live)
+}
+
+//SetCells sets the state of the cells at once, the points outside the area are ignored
+func (u *BaseUniverse) SetCells(points []Point, live bool) {
+	u.area.Lock()
+	for _, p := range points {
+		if p.X < 0 || p.Y < 0 || p.X >= u.area.Width || p.Y >= u.area.Height {
+			continue
+		}
+		u.setCell(p.X, p.Y, live, 1)
+	}
+	u.publishArea(Area{})
 	u.area.Unlock()
+	u.clearSource()
+	u.reactivate()
 	u.refreshView()
 }
 
+//InvertAll inverses the state of every cell, returns when the field is inverted
+//the cells become live with the first color of the multi-color rules
+func (u *BaseUniverse) InvertAll() {
+	done := make(chan bool)
+	u.controlCh <- func() {
+		u.area.Lock()
+		u.walkArea(func(x int, y int, e Cell) {
+			u.setCell(x, y, !bool(e), 1)
+		})
+		u.publishArea(Area{})
+		u.area.Unlock()
+		u.setLiveCells(u.liveCells())
+		u.clearSource()
+		u.reactivate()
+		u.refreshView()
+		done <- true
+	}
+	<-done
+}
+
 //RegisterViewer registers the viewer - the universe will call the viewer when the state is changed
 func (u *BaseUniverse) RegisterViewer(v Viewer) {
 	u.views = append(u.views, v)
@@ -186,19 +362,95 @@ func (u *BaseUniverse) StateCh() chan Status {
 	return u.stateCh
 }
 
+//SubscribeMode returns the channel receiving the new running mode on each change
+//the transient step mode is not sent, so the subscribers see manual, run and finished modes only
+//the mode is dropped if the channel buffer is full, so the universe never waits for the subscriber
+//the channel is closed when the universe is closed
+func (u *BaseUniverse) SubscribeMode() <-chan RunningState {
+	ch := make(chan RunningState, DefModeChBuffer)
+	u.state.Lock()
+	u.modeChs = append(u.modeChs, ch)
+	u.state.Unlock()
+	return ch
+}
+
 //Status returns current universe status represented by Status struct
 func (u *BaseUniverse) Status() Status {
+	u.state.Lock()
+	defer u.state.Unlock()
 	return u.state.Status
 }
 
 //Status returns current universe configuration represented by Options struct
 func (u *BaseUniverse) Options() Options {
+	u.state.Lock()
+	defer u.state.Unlock()
 	return u.options
 }
 
 //Area returns current universe area (field where cells is living)
+//the area is the deep copy of the snapshot published after the last change
+//so it is safe to read it while the simulation is running and the caller can modify it freely
 func (u *BaseUniverse) Area() Area {
-	return u.area.Area
+	return copyArea(u.snapshotArea())
+}
+
+//SetArea replaces the field by the copy of the area, returns when the field is replaced
+//the multi-color rules take the area colors, the cells without colors get the first one
+//returns ErrAreaSizeMismatch if the area dimensions differ from the field ones, nothing is changed in this case
+func (u *BaseUniverse) SetArea(a Area) error {
+	if a.Width != u.options.Width || a.Height != u.options.Height || len(a.Entities) != a.Height ||
+		a.Colors != nil && len(a.Colors) != a.Height {
+		return ErrAreaSizeMismatch
+	}
+	for y := range a.Entities {
+		if len(a.Entities[y]) != a.Width || a.Colors != nil && len(a.Colors[y]) != a.Width {
+			return ErrAreaSizeMismatch
+		}
+	}
+	done := make(chan bool)
+	u.controlCh <- func() {
+		u.area.Lock()
+		u.walkArea(func(x int, y int, _ Cell) {
+			color := uint8(1)
+			if a.Colors != nil {
+				color = a.Colors[y][x]
+			}
+			u.setCell(x, y, bool(a.Entities[y][x]), color)
+		})
+		u.publishArea(Area{})
+		u.area.Unlock()
+		u.setLiveCells(u.liveCells())
+		u.clearSource()
+		u.reactivate()
+		u.refreshView()
+		done <- true
+	}
+	<-done
+	return nil
+}
+
+//snapshotArea returns the area snapshot published after the last change, it is shared and should not be modified
+func (u *BaseUniverse) snapshotArea() Area {
+	return u.snapshot.Load().(areaSnapshot).area
+}
+
+//Changes returns the cells which were born and died on the last step
+//both are empty if the area was changed after the last step by other means (settling, editing, clearing)
+func (u *BaseUniverse) Changes() (born []Point, died []Point) {
+	s := u.snapshot.Load().(areaSnapshot)
+	diff, err := Diff(s.prev, s.area)
+	if err != nil {
+		return nil, nil
+	}
+	for _, p := range diff {
+		if s.area.Entities[p.Y][p.X] {
+			born = append(born, p)
+		} else {
+			died = append(died, p)
+		}
+	}
+	return
 }
 
 //Run starts the universe simulation, returns immediately
@@ -206,6 +458,73 @@ func (u *BaseUniverse) Run() {
 	u.controlCh <- u.run
 }
 
+//FastRun starts the universe simulation without the interval delays and the views refreshing, returns immediately
+//the views are refreshed once when the simulation is finished or stopped
+func (u *BaseUniverse) FastRun() {
+	u.controlCh <- u.fastRun
+}
+
+//CountComponents finds the connected clusters of the live cells with the active boundary, returns immediately
+//the clusters sizes are stored to Status.Components until the next step or clearing
+func (u *BaseUniverse) CountComponents(c Connectivity) {
+	u.controlCh <- func() {
+		components := Components(u.snapshotArea(), c, u.Boundary())
+		u.state.Lock()
+		u.state.Components = components
+		u.state.Unlock()
+		u.refreshView()
+	}
+}
+
+//Rule returns the active rule
+func (u *BaseUniverse) Rule() Rule {
+	u.state.Lock()
+	defer u.state.Unlock()
+	return u.rule
+}
+
+//Source returns where the field is loaded from, the empty Source if the field is drawn or cleared since then
+func (u *BaseUniverse) Source() Source {
+	u.state.Lock()
+	defer u.state.Unlock()
+	return u.source
+}
+
+//SetSource sets where the field is loaded from, it's cleared when the cells are changed by hand or the field is cleared
+func (u *BaseUniverse) SetSource(s Source) {
+	u.state.Lock()
+	u.source = s
+	u.state.Unlock()
+	u.refreshView()
+}
+
+//clearSource forgets where the field is loaded from
+func (u *BaseUniverse) clearSource() {
+	u.state.Lock()
+	u.source = Source{}
+	u.state.Unlock()
+}
+
+//Boundary returns the active boundary mode
+func (u *BaseUniverse) Boundary() Boundary {
+	u.state.Lock()
+	defer u.state.Unlock()
+	return u.boundary
+}
+
+//SetRule replaces the active rule, returns immediately
+//the field is kept untouched, the next step uses the new rule
+func (u *BaseUniverse) SetRule(r Rule) {
+	u.controlCh <- func() {
+		u.state.Lock()
+		u.rule = r
+		u.setAdvanced(AdvRule, r.String())
+		u.state.Unlock()
+		u.applyColors()
+		u.refreshView()
+	}
+}
+
 //Stop stops the universe simulation, returns immediately
 //the Status struct will be written the stateCh on finish
 func (u *BaseUniverse) Stop() {
@@ -213,9 +532,50 @@ func (u *BaseUniverse) Stop() {
 }
 
 //Step do one simulation step, returns immediately
+//the running simulation is paused before the step, so the universe is always left in the manual mode:
+//Run -> Manual -> Step -> Manual, Manual -> Step -> Manual
 //the Status struct will be written to the stateCh on start and on finish
+//all the engines give the same field after the step, see the package documentation
 func (u *BaseUniverse) Step() {
-	u.controlCh <- u.step
+	u.controlCh <- u.manualStep
+}
+
+//StepSync does one simulation step as Step does, returns when the step is done
+func (u *BaseUniverse) StepSync() {
+	done := make(chan bool)
+	u.controlCh <- func() {
+		u.manualStep()
+		done <- true
+	}
+	<-done
+}
+
+//StepWithin pauses the running simulation and does as many steps as fit in the budget d, returns the count of the steps
+//only the whole generations are done: at least one step is done, the next step isn't started if it's expected to exceed d
+//the steps are stopped when the simulation is finished, the views are refreshed once after the steps
+func (u *BaseUniverse) StepWithin(d time.Duration) int {
+	done := make(chan int)
+	u.controlCh <- func() {
+		u.stop()
+		done <- u.stepsWithin(d)
+	}
+	return <-done
+}
+
+//Iterate pauses the running simulation and does up to n steps calling fn with the generation and the field after each one
+//the iteration is stopped when fn returns false or the simulation is finished, no steps are done on the finished universe
+//the area passed to fn is the copy owned by fn, it can be kept or changed without affecting the universe
+//fn is called outside the main loop, so it can call the universe methods
+func (u *BaseUniverse) Iterate(n int, fn func(gen int, a Area) bool) {
+	for i := 0; i < n; i++ {
+		if u.Status().RunningMode == RunningStateFinished {
+			return
+		}
+		u.StepSync()
+		if !fn(u.Status().IterationNum, u.Area()) {
+			return
+		}
+	}
 }
 
 //Clear clears the universe (kill all cells and reset all counters), returns immediately
@@ -243,16 +603,64 @@ func (u *BaseUniverse) mainLoop() {
 	}
 	close(u.closeCh)
 	close(u.controlCh)
+	u.state.Lock()
+	for _, ch := range u.modeChs {
+		close(ch)
+	}
+	u.modeChs = nil
+	u.state.Unlock()
 }
 
 //settle places the Cell at position x,y
+//the optional third coordinate is the color of the live cell for the multi-color rules
 func (u *BaseUniverse) settle(vc [][]int, entity Cell) {
 	for _, v := range vc {
 		if v[0] >= u.area.Width || v[1] >= u.area.Height {
 			continue
 		}
-		u.area.Entities[v[1]][v[0]] = entity
+		u.setCell(v[0], v[1], bool(entity), coordinateColor(v))
+	}
+}
+
+//setCell sets the cell state, the live cell gets the color if the multi-color rule is active
+//the colors outside 1..Rule.Colors are replaced by the first color
+//should be called under the area lock
+func (u *BaseUniverse) setCell(x int, y int, live bool, color uint8) {
+	u.area.Entities[y][x] = Cell(live)
+	if !live || u.area.Colors == nil {
+		return
+	}
+	if color < 1 || int(color) > u.rule.Colors {
+		color = 1
+	}
+	u.area.Colors[y][x] = color
+}
+
+//coordinateColor returns the color from the optional third coordinate, 1 if it's not set
+func coordinateColor(v []int) uint8 {
+	if len(v) > 2 && v[2] > 0 && v[2] <= MaxColors {
+		return uint8(v[2])
+	}
+	return 1
+}
+
+//applyColors allocates the colors layer when the multi-color rule is activated, the live cells get the first color
+//the layer is dropped for the two-state rules, the area snapshot is republished
+func (u *BaseUniverse) applyColors() {
+	u.area.Lock()
+	defer u.area.Unlock()
+	switch {
+	case u.rule.Colors == 0:
+		u.area.Colors = nil
+	case u.area.Colors == nil:
+		u.area.Colors = createColors(u.area.Width, u.area.Height)
+		u.walkArea(func(x int, y int, e Cell) {
+			if e {
+				u.area.Colors[y][x] = 1
+			}
+		})
 	}
+	u.publishArea(Area{})
 }
 
 //liveCells calculates the count of live cells
@@ -268,55 +676,193 @@ func (u *BaseUniverse) liveCells() int {
 	return liveCells
 }
 
+//setLiveCells writes the live cells count to the status, it is read by the viewers goroutines
+func (u *BaseUniverse) setLiveCells(n int) {
+	u.state.Lock()
+	u.state.LiveCells = n
+	u.state.Unlock()
+}
+
 //switchRunningState switch the state of the universe to RunningState
 //also writes the new state to the stateCh to signal upper control software
+//the mode subscribers are notified if the mode is changed
 func (u *BaseUniverse) switchRunningState(to RunningState) {
 	u.state.Lock()
-	u.state.RunningMode = to
-	st := u.state.Status
+	st := u.setRunningMode(to)
 	u.state.Unlock()
 	if u.stateCh != nil {
 		u.stateCh <- st
 	}
 }
 
+//setRunningMode changes the running mode and notifies the mode subscribers, returns the new status
+//should be called under the state lock
+func (u *BaseUniverse) setRunningMode(to RunningState) Status {
+	u.state.RunningMode = to
+	if to != RunningStateStep && to != u.notifiedMode {
+		u.notifiedMode = to
+		for _, ch := range u.modeChs {
+			select {
+			case ch <- to:
+			default:
+			}
+		}
+	}
+	return u.state.Status
+}
+
+//reactivate switches the finished universe to the manual mode after the field is edited, the finish reason is dropped
+//the check and the switch are done at once so the universe started meanwhile is not stopped
+func (u *BaseUniverse) reactivate() {
+	u.state.Lock()
+	finished := u.state.RunningMode == RunningStateFinished
+	var st Status
+	if finished {
+		u.state.FinishReason = FinishReasonNone
+		u.state.Period = 0
+		st = u.setRunningMode(RunningStateManual)
+	}
+	u.state.Unlock()
+	if finished && u.stateCh != nil {
+		u.stateCh <- st
+	}
+}
+
 //run starts the universe simulation
 //simulation will stop on Stop() calling or when the boundary conditions are reached
 func (u *BaseUniverse) run() {
+	//the running universe isn't started twice, so the steps aren't doubled by the second cycle
+	if u.state.RunningMode == RunningStateRun {
+		return
+	}
+	u.loop(false, nil)
+}
+
+//fastRun starts the universe simulation with no delays between the steps and with no views refreshing
+//the views are refreshed once when the running cycle is over
+//the running universe is switched to the fast run, the fast running one is left as is
+func (u *BaseUniverse) fastRun() {
+	if u.state.RunningMode == RunningStateRun && u.state.FastRun {
+		return
+	}
+	//the stale cycle is finished before the views are informed about the new one
+	u.exitLoop()
+	u.state.Lock()
+	u.state.FastRun = true
+	u.state.Unlock()
+	//the views are informed about the fast run before going quiet
+	u.refreshView()
+	atomic.StoreInt32(&u.quiet, 1)
+	u.loop(true, func() {
+		atomic.StoreInt32(&u.quiet, 0)
+		u.state.Lock()
+		u.state.FastRun = false
+		u.state.Unlock()
+		u.refreshView()
+	})
+}
+
+//loop starts the running cycle as a goroutine, the steps are separated by the options interval unless fast is set
+//the previous cycle (e.g. the stopped one still sleeping the interval) is superseded and exits without stepping
+//onExit (if any) is executed by the main loop when the running cycle is over or superseded
+//should be called by the main loop
+func (u *BaseUniverse) loop(fast bool, onExit func()) {
+	u.exitLoop()
+	u.state.Lock()
+	u.loopID++
+	id := u.loopID
+	u.state.Unlock()
+	u.loopExit = onExit
+	u.switchRunningState(RunningStateRun)
 	go func() {
-		u.switchRunningState(RunningStateRun)
 		skipped := 0
 		done := make(chan bool)
 		defer close(done)
 		for {
-			mode := u.state.RunningMode
-			if mode != RunningStateRun && mode != RunningStateStep {
+			u.state.Lock()
+			mode, stale := u.state.RunningMode, u.loopID != id
+			u.state.Unlock()
+			if stale || mode != RunningStateRun && mode != RunningStateStep {
 				break
 			}
-			if skipped > u.options.MaxSkippedTicks {
+			//the options can be changed while running
+			o := u.Options()
+			if skipped > o.MaxSkippedTicks {
+				u.state.Lock()
+				u.state.FinishReason = FinishReasonSkipped
+				u.state.Unlock()
 				u.switchRunningState(RunningStateFinished)
-				//todo write the warning message
 				break
 			}
+			tick := time.Now()
+			budgeted := false
 			//skip the tick if the universe is still in the calculation mode
 			if mode != RunningStateStep {
 				skipped = 0
 				u.controlCh <- func() {
-					u.step()
+					//the universe could be paused while the step was waiting in the queue
+					if u.state.RunningMode == RunningStateRun && u.loopID == id {
+						if budget := u.advancedInt(AdvStepBudget, 0); budget > 0 && !fast {
+							budgeted = true
+							u.stepsWithin(time.Duration(budget) * time.Millisecond)
+						} else {
+							u.step()
+						}
+					}
 					done <- true
 				}
 				<-done
 			} else {
 				skipped++
 			}
-			if u.options.Interval > 0 {
-				time.Sleep(u.options.Interval)
+			if !fast && o.Interval > 0 {
+				if budgeted {
+					//the interval is counted from the tick start, so the frames are steady while the steps fit the budget
+					time.Sleep(o.Interval - time.Since(tick))
+				} else {
+					time.Sleep(o.Interval)
+				}
+			}
+		}
+		if onExit != nil {
+			u.controlCh <- func() {
+				//the superseded cycle is exited by the next one
+				if u.loopID == id {
+					u.exitLoop()
+				}
 			}
 		}
-
 	}()
 }
 
+//exitLoop executes the exit handler of the running cycle once
+//should be called by the main loop
+func (u *BaseUniverse) exitLoop() {
+	if onExit := u.loopExit; onExit != nil {
+		u.loopExit = nil
+		onExit()
+	}
+}
+
+//stepsWithin does the steps until the next one is expected to exceed the budget d, at least one step is done
+//the next step is expected to take the average time of the done ones
+//should be called by the main loop
+func (u *BaseUniverse) stepsWithin(d time.Duration) (n int) {
+	start := time.Now()
+	quiet := atomic.SwapInt32(&u.quiet, 1)
+	for {
+		u.step()
+		n++
+		elapsed := time.Since(start)
+		if u.state.RunningMode == RunningStateFinished || elapsed+elapsed/time.Duration(n) > d {
+			break
+		}
+	}
+	atomic.StoreInt32(&u.quiet, quiet)
+	u.refreshView()
+	return
+}
+
 //stop stops the universe running cycle
 func (u *BaseUniverse) stop() {
 	if u.state.RunningMode == RunningStateRun {
@@ -324,13 +870,33 @@ func (u *BaseUniverse) stop() {
 	}
 }
 
+//manualStep pauses the running simulation and does one step
+func (u *BaseUniverse) manualStep() {
+	u.stop()
+	u.step()
+}
+
 //step does the new one state calculation for entire universe
 func (u *BaseUniverse) step() {
 
 	finished := false
 	rm := u.state.RunningMode
-	maxIter := u.options.MaxSteps
+	if rm == RunningStateFinished {
+		//the step from the finished state continues manually
+		rm = RunningStateManual
+	}
+	maxPeriod := u.advancedInt(AdvMaxPeriod, 0)
+	//Status is read by the other goroutines while the universe is running
+	u.state.Lock()
 	u.state.IterationNum++
+	first := u.state.IterationNum == 1
+	u.state.FinishReason = FinishReasonNone
+	u.state.Period = 0
+	u.state.Components = nil
+	u.state.Unlock()
+	if first {
+		u.seed = u.snapshotArea()
+	}
 	defer func() {
 		if finished {
 			u.switchRunningState(RunningStateFinished)
@@ -340,15 +906,100 @@ func (u *BaseUniverse) step() {
 		u.refreshView()
 	}()
 
-	if maxIter != 0 && u.state.IterationNum >= maxIter {
-		finished = true
-		return
-	}
 	u.switchRunningState(RunningStateStep)
+	prev := u.snapshotArea()
 	isAlive, changed := u.nextIteration()
-	if !isAlive || !changed {
+	u.updateAvgIterationTime()
+	u.area.Lock()
+	period := u.detectCycle(prev, maxPeriod)
+	start := time.Now()
+	u.publishArea(prev)
+	publishTime := time.Since(start)
+	if size := u.advancedInt(AdvHistory, 0); size > 0 {
+		maxBytes := u.advancedInt(AdvHistoryMB, DefHistoryMB) << 20
+		if len(u.generations.areas) == 0 {
+			//the history starts with the generation the step is done from
+			u.generations.push(u.state.IterationNum-1, prev, size, maxBytes)
+		}
+		u.generations.push(u.state.IterationNum, u.snapshotArea(), size, maxBytes)
+	} else {
+		u.generations = generationHistory{}
+	}
+	historyBytes := u.generations.bytes
+	u.area.Unlock()
+	memory := u.memoryUsage() + historyBytes
+	u.state.Lock()
+	u.state.PublishTime = publishTime
+	u.state.Memory = memory
+	u.state.HistoryMemory = historyBytes
+	u.state.Unlock()
+
+	reason := u.finishReason(isAlive, changed, period)
+	if reason == FinishReasonMaxSteps {
+		switch u.advancedString(AdvOnMaxSteps, OnMaxStepsStop) {
+		case OnMaxStepsContinue:
+			return
+		case OnMaxStepsReset:
+			u.reset()
+			return
+		}
+	}
+	if reason != FinishReasonNone {
 		finished = true
+		u.state.Lock()
+		u.state.FinishReason = reason
+		if reason == FinishReasonCycle {
+			u.state.Period = period
+		}
+		u.state.Unlock()
+	}
+}
+
+//finishReason returns the condition finishing the simulation after the step, FinishReasonNone if the simulation goes on
+//when several conditions are met the first one is reported in the order:
+//extinction, stable state, cycle, cell limit, steps limit
+func (u *BaseUniverse) finishReason(isAlive bool, changed bool, period int) FinishReason {
+	maxIter := u.options.MaxSteps
+	maxCells := u.advancedInt(AdvMaxCells, 0)
+	switch {
+	case !isAlive:
+		return FinishReasonExtinct
+	case !changed:
+		return FinishReasonStable
+	case period > 0:
+		return FinishReasonCycle
+	case maxCells > 0 && u.state.LiveCells > maxCells:
+		return FinishReasonCellLimit
+	case maxIter != 0 && u.state.IterationNum >= maxIter:
+		return FinishReasonMaxSteps
+	}
+	return FinishReasonNone
+}
+
+//detectCycle adds the area hash to the history and returns the period of the repeated area state, 0 if the state is new
+//prev is the area before the step, it starts the history
+//the history keeps the last maxPeriod states, no detection if maxPeriod is 0
+//should be called under the area lock
+func (u *BaseUniverse) detectCycle(prev Area, maxPeriod int) (period int) {
+	if maxPeriod <= 0 {
+		u.history = nil
+		return 0
+	}
+	if len(u.history) == 0 {
+		u.history = append(u.history, areaHash(prev))
+	}
+	h := areaHash(u.area.Area)
+	for i := len(u.history) - 1; i >= 0; i-- {
+		if u.history[i] == h {
+			period = len(u.history) - i
+			break
+		}
 	}
+	u.history = append(u.history, h)
+	if len(u.history) > maxPeriod {
+		u.history = u.history[len(u.history)-maxPeriod:]
+	}
+	return
 }
 
 //clear clears the unvierse data, reset all counters
@@ -358,10 +1009,18 @@ func (u *BaseUniverse) clear() {
 
 	u.state.IterationNum = 0
 	u.state.LiveCells = 0
+	u.state.IterationTime = 0
+	u.state.AvgIterationTime = 0
+	u.state.StepHistogram = StepHistogram{}
+	u.steps = stepHistory{}
+	u.state.PublishTime = 0
+	u.state.FinishReason = FinishReasonNone
+	u.state.Components = nil
+	u.source = Source{}
 	u.walkArea(func(x int, y int, e Cell) {
 		u.area.Entities[y][x] = false
 	})
-	u.state.RunningMode = RunningStateManual
+	u.publishArea(Area{})
 	u.area.Unlock()
 	u.state.Unlock()
 	u.switchRunningState(RunningStateManual)
@@ -369,6 +1028,50 @@ func (u *BaseUniverse) clear() {
 
 }
 
+//reset restores the initial seed and resets the iterations counter, the running mode is not changed
+func (u *BaseUniverse) reset() {
+	u.area.Lock()
+	for y := range u.seed.Entities {
+		copy(u.area.Entities[y], u.seed.Entities[y])
+		if u.seed.Colors != nil && u.area.Colors != nil {
+			copy(u.area.Colors[y], u.seed.Colors[y])
+		}
+	}
+	u.publishArea(Area{})
+	u.area.Unlock()
+	u.state.Lock()
+	u.state.IterationNum = 0
+	u.state.LiveCells = u.seedLiveCells()
+	u.state.Unlock()
+}
+
+//seedLiveCells calculates the count of live cells in the initial seed
+func (u *BaseUniverse) seedLiveCells() int {
+	liveCells := 0
+	for y := range u.seed.Entities {
+		for _, e := range u.seed.Entities[y] {
+			if e {
+				liveCells++
+			}
+		}
+	}
+	return liveCells
+}
+
+//updateAvgIterationTime adds the last iteration time to the moving average and the step histogram
+func (u *BaseUniverse) updateAvgIterationTime() {
+	u.state.Lock()
+	defer u.state.Unlock()
+	u.steps.add(&u.state.StepHistogram, u.state.IterationTime)
+	if u.state.AvgIterationTime == 0 {
+		u.state.AvgIterationTime = u.state.IterationTime
+		return
+	}
+	//the smoothing factor is 2/(N+1)
+	delta := u.state.IterationTime - u.state.AvgIterationTime
+	u.state.AvgIterationTime += delta * 2 / (DefAvgIterations + 1)
+}
+
 //_nextIteration does one simulation cycle
 //walking the area and calculating the next state for the each cell
 //the simplest implementation: creates the new area buffer with full size on each call
@@ -378,6 +1081,9 @@ func (u *BaseUniverse) _nextIteration() (hasLiveEnitities bool, changed bool) {
 	defer u.area.Unlock()
 	start := time.Now()
 	a := createArea(u.area.Width, u.area.Height)
+	if u.area.Colors != nil {
+		a.Colors = createColors(u.area.Width, u.area.Height)
+	}
 	liveCellls := 0
 	u.walkArea(func(x int, y int, e Cell) {
 		nextState := u.cellNextState(x, y)
@@ -386,14 +1092,38 @@ func (u *BaseUniverse) _nextIteration() (hasLiveEnitities bool, changed bool) {
 		a.Entities[y][x] = Cell(nextState)
 		if nextState {
 			liveCellls++
+			if a.Colors != nil {
+				a.Colors[y][x] = u.cellNextColor(x, y)
+			}
 		}
 	})
 	u.area.Entities = a.Entities
+	u.area.Colors = a.Colors
+	u.state.Lock()
 	u.state.LiveCells = liveCellls
 	u.state.IterationTime = time.Since(start)
+	u.state.Unlock()
 	return
 }
 
+//_memoryUsage estimates the bytes of the area, the new area is allocated on each step and replaces the old one
+func (u *BaseUniverse) _memoryUsage() int {
+	return areaBytes(u.area.Area)
+}
+
+//publishArea stores the copy of the area as the snapshot returned by Area()
+//prev is the area before the step, the empty area if the area is changed not by the step
+//the previous snapshot is dropped, so the readers always get the latest state
+//the cycle detection history is dropped when the area is changed not by the step
+//should be called under the area lock
+func (u *BaseUniverse) publishArea(prev Area) {
+	if prev.Entities == nil {
+		u.history = nil
+		u.generations = generationHistory{}
+	}
+	u.snapshot.Store(areaSnapshot{area: copyArea(u.area.Area), prev: prev})
+}
+
 //walkArea walk the entire area and calls the cb function for each cell
 func (u *BaseUniverse) walkArea(cb func(x int, y int, entity Cell)) {
 	for y := range u.area.Entities {
@@ -405,9 +1135,40 @@ func (u *BaseUniverse) walkArea(cb func(x int, y int, entity Cell)) {
 
 //cellNextState calculates the next state for the cell
 func (u *BaseUniverse) cellNextState(x int, y int) (live bool) {
-	//calculate neighbors
+	return u.rule.nextState(bool(u.area.Entities[y][x]), liveNeighbours(u.area.Area, x, y, u.wrapX, u.wrapY))
+}
+
+//cellNextColor returns the color of the cell which is live on the next step
+//the survivor keeps its color, the newborn gets the color from its parents
+func (u *BaseUniverse) cellNextColor(x int, y int) uint8 {
+	if u.area.Entities[y][x] {
+		return u.area.Colors[y][x]
+	}
+	counts := [MaxColors + 1]int{}
+	for i := -1; i < 2; i++ {
+		for j := -1; j < 2; j++ {
+			nx, ny := x+i, y+j
+			if u.wrapX {
+				nx = (nx + u.area.Width) % u.area.Width
+			}
+			if u.wrapY {
+				ny = (ny + u.area.Height) % u.area.Height
+			}
+			if (i == 0 && j == 0) || nx < 0 || ny < 0 || nx >= u.area.Width || ny >= u.area.Height {
+				continue
+			}
+			if u.area.Entities[ny][nx] {
+				counts[u.area.Colors[ny][nx]]++
+			}
+		}
+	}
+	return newbornColor(&counts, u.rule.Colors)
+}
+
+//liveNeighbours calculates the count of live neighbours of the cell at x, y
+//the coordinates outside the area are wrapped to the opposite edge if wrapX/wrapY is set
+func liveNeighbours(area Area, x int, y int, wrapX bool, wrapY bool) int {
 	liveNeighbours := 0
-	area := u.area.Area
 	for i := -1; i < 2; i++ {
 		for j := -1; j < 2; j++ {
 			//skip my position
@@ -416,6 +1177,12 @@ func (u *BaseUniverse) cellNextState(x int, y int) (live bool) {
 			}
 			nx := x + i
 			ny := y + j
+			if wrapX {
+				nx = (nx + area.Width) % area.Width
+			}
+			if wrapY {
+				ny = (ny + area.Height) % area.Height
+			}
 			//skip coordinates outside the area
 			if nx < 0 || ny < 0 || nx >= area.Width || ny >= area.Height {
 				continue
@@ -425,22 +1192,47 @@ func (u *BaseUniverse) cellNextState(x int, y int) (live bool) {
 			}
 		}
 	}
+	return liveNeighbours
+}
+
+//setAdvanced sets the advanced option
+//the options map is replaced with the updated copy, so the copies returned by Options() are never changed
+//should be called by the main loop under the state lock
+func (u *BaseUniverse) setAdvanced(name string, value interface{}) {
+	advanced := make(map[string]interface{}, len(u.options.Advanced)+1)
+	for k, v := range u.options.Advanced {
+		advanced[k] = v
+	}
+	advanced[name] = value
+	u.options.Advanced = advanced
+}
 
-	if liveNeighbours < 2 {
-		return false
-	} else if liveNeighbours > 3 {
-		return false
-	} else if liveNeighbours == 3 {
-		return true
-	} else if liveNeighbours == 2 && area.Entities[y][x] {
-		return true
+//advancedInt returns the integer advanced option or def if the option is not set
+func (u *BaseUniverse) advancedInt(name string, def int) int {
+	switch v := u.options.Advanced[name].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
 	}
+	return def
+}
 
-	return false
+//advancedString returns the string advanced option or def if the option is not set
+func (u *BaseUniverse) advancedString(name string, def string) string {
+	if v, ok := u.options.Advanced[name].(string); ok {
+		return v
+	}
+	return def
 }
 
 //refreshView calls Refresh event for all registered views
 func (u *BaseUniverse) refreshView() {
+	if atomic.LoadInt32(&u.quiet) != 0 {
+		return
+	}
 	for _, v := range u.views {
 		v.Refresh()
 	}
@@ -457,3 +1249,14 @@ func createArea(width int, height int) Area {
 	}
 	return area
 }
+
+//createColors allocates the colors layer of the area
+func createColors(width int, height int) [][]uint8 {
+	colors := make([][]uint8, height)
+	b := make([]uint8, width*height)
+	for i := range colors {
+		start := width * i
+		colors[i] = b[start : start+width : start+width]
+	}
+	return colors
+}
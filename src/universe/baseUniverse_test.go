@@ -0,0 +1,777 @@
+package universe
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBaseUniverse_AreaSnapshot(t *testing.T) {
+	for _, e := range engineNames() {
+		t.Run(e, func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height, o.MaxSteps = 50, 50, 100
+			stateCh := newStateCh()
+			u := engines[e](o, stateCh)
+			u.Settle(testTemplate.Coordinates)
+			u.Run()
+			done := make(chan bool)
+			go func() {
+				for {
+					select {
+					case <-done:
+						return
+					default:
+						a := u.Area()
+						if len(a.Entities) != o.Height {
+							t.Errorf("unexpected area height: %v", len(a.Entities))
+						}
+					}
+				}
+			}()
+			waitFinished(stateCh)
+			close(done)
+			u.Close()
+		})
+	}
+}
+
+func TestBaseUniverse_MaxCells(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 10, 10
+	o.Advanced = map[string]interface{}{AdvMaxCells: 2}
+	stateCh := newStateCh()
+	u := newBaseUniverse(o, stateCh)
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	u.Run()
+	st := waitFinished(stateCh)
+	if st.FinishReason != FinishReasonCellLimit {
+		t.Errorf("unexpected finish reason: %q", st.FinishReason)
+	}
+	if st.IterationNum != 1 {
+		t.Errorf("expected to finish on the first step, finished on %v", st.IterationNum)
+	}
+	u.Close()
+}
+
+func TestBaseUniverse_FinishReason(t *testing.T) {
+	blinker := [][]int{{4, 5}, {5, 5}, {6, 5}}
+	tests := []struct {
+		name          string
+		cells         [][]int
+		maxSteps      int
+		advanced      map[string]interface{}
+		wantReason    FinishReason
+		wantIteration int
+		wantPeriod    int
+	}{
+		{"extinct over max steps", [][]int{{5, 5}}, 1, map[string]interface{}{AdvMaxPeriod: 2}, FinishReasonExtinct, 1, 0},
+		{"stable over cycle and max steps", [][]int{{4, 4}, {5, 4}, {4, 5}, {5, 5}}, 1, map[string]interface{}{AdvMaxPeriod: 2}, FinishReasonStable, 1, 0},
+		{"cycle over max steps", blinker, 2, map[string]interface{}{AdvMaxPeriod: 2}, FinishReasonCycle, 2, 2},
+		{"cycle within max period", blinker, 0, map[string]interface{}{AdvMaxPeriod: 5}, FinishReasonCycle, 2, 2},
+		{"cell limit over max steps", blinker, 1, map[string]interface{}{AdvMaxCells: 2}, FinishReasonCellLimit, 1, 0},
+		{"max steps", blinker, 3, nil, FinishReasonMaxSteps, 3, 0},
+	}
+	for _, e := range engineNames() {
+		for _, tt := range tests {
+			t.Run(e+"/"+tt.name, func(t *testing.T) {
+				o := newUniverseOptions()
+				o.Width, o.Height, o.MaxSteps = 10, 10, tt.maxSteps
+				o.Advanced = tt.advanced
+				stateCh := newStateCh()
+				u := engines[e](o, stateCh)
+				u.Settle(tt.cells)
+				u.Run()
+				st := waitFinished(stateCh)
+				if st.FinishReason != tt.wantReason || st.IterationNum != tt.wantIteration || st.Period != tt.wantPeriod {
+					t.Errorf("got %q on iteration %v with period %v, want %q on iteration %v with period %v",
+						st.FinishReason, st.IterationNum, st.Period, tt.wantReason, tt.wantIteration, tt.wantPeriod)
+				}
+				u.Close()
+			})
+		}
+	}
+}
+
+func TestNewBaseUniverse_Dimensions(t *testing.T) {
+	tests := []struct {
+		width   int
+		height  int
+		wantErr error
+	}{
+		{0, 10, ErrInvalidOptions},
+		{10, 0, ErrInvalidOptions},
+		{-1, 10, ErrInvalidOptions},
+		{10, -5, ErrInvalidOptions},
+		{1, 1, nil},
+	}
+	constructors := map[string]func(o *Options, stateCh chan Status) (Universe, error){
+		"simple":        NewSimpleUniverse,
+		"smallBuff":     NewSmallBuffUniverse,
+		"multithreaded": NewMultithreadedUniverse,
+		"hashlife":      NewHashlifeUniverse,
+	}
+	for _, tt := range tests {
+		o := newUniverseOptions()
+		o.Width, o.Height = tt.width, tt.height
+		u, err := NewBaseUniverse(o, nil)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%v x %v: got error %v, want %v", tt.width, tt.height, err, tt.wantErr)
+		}
+		if err == nil {
+			u.Close()
+		}
+		for name, c := range constructors {
+			u, err := c(o, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s %v x %v: got error %v, want %v", name, tt.width, tt.height, err, tt.wantErr)
+			}
+			if err == nil {
+				u.Close()
+			}
+		}
+	}
+}
+
+func TestBaseUniverse_Memory(t *testing.T) {
+	for _, e := range engineNames() {
+		t.Run(e, func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height = 20, 10
+			stateCh := newStateCh()
+			u := engines[e](o, stateCh)
+			u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+			u.Step()
+			waitMode(stateCh, RunningStateManual)
+			//each engine keeps the area at least
+			if m := u.Status().Memory; m < o.Width*o.Height {
+				t.Errorf("got memory %d, want at least %d", m, o.Width*o.Height)
+			}
+			u.Close()
+		})
+	}
+}
+
+//waitFinished reads the states until the universe is finished and returns the final state
+func waitFinished(stateCh chan Status) Status {
+	return waitMode(stateCh, RunningStateFinished)
+}
+
+func TestBaseUniverse_StepWhilePaused(t *testing.T) {
+	stateCh := newStateCh()
+	u := newBaseUniverse(newUniverseOptions(), stateCh)
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	u.Step()
+	want := []RunningState{RunningStateStep, RunningStateManual}
+	for i, w := range want {
+		if st := <-stateCh; st.RunningMode != w {
+			t.Errorf("state %v: got mode %v, want %v", i, st.RunningMode, w)
+		}
+	}
+	if st := u.Status(); st.IterationNum != 1 || st.RunningMode != RunningStateManual {
+		t.Errorf("got iteration %v in mode %v, want iteration 1 in manual mode", st.IterationNum, st.RunningMode)
+	}
+	u.Close()
+}
+
+func TestBaseUniverse_StepWhileRunning(t *testing.T) {
+	o := newUniverseOptions()
+	o.Interval = time.Millisecond * 10
+	o.MaxSteps = 0
+	stateCh := newStateCh()
+	u := newBaseUniverse(o, stateCh)
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	u.Run()
+	if st := <-stateCh; st.RunningMode != RunningStateRun {
+		t.Fatalf("got mode %v, want running", st.RunningMode)
+	}
+	u.Step()
+	//the simulation is paused first, then the single step is done
+	paused := <-stateCh
+	for paused.RunningMode != RunningStateManual {
+		paused = <-stateCh
+	}
+	if st := <-stateCh; st.RunningMode != RunningStateStep {
+		t.Errorf("got mode %v after pausing, want step", st.RunningMode)
+	}
+	if st := <-stateCh; st.RunningMode != RunningStateManual || st.IterationNum != paused.IterationNum+1 {
+		t.Errorf("got iteration %v in mode %v, want iteration %v in manual mode", st.IterationNum, st.RunningMode, paused.IterationNum+1)
+	}
+	//the universe stays paused
+	time.Sleep(o.Interval * 5)
+	if st := u.Status(); st.IterationNum != paused.IterationNum+1 || st.RunningMode != RunningStateManual {
+		t.Errorf("got iteration %v in mode %v, want iteration %v in manual mode", st.IterationNum, st.RunningMode, paused.IterationNum+1)
+	}
+	u.Close()
+}
+
+func TestBaseUniverse_RunTwice(t *testing.T) {
+	o := newUniverseOptions()
+	o.Interval = time.Millisecond * 50
+	o.MaxSteps = 0
+	u := newBaseUniverse(o, nil)
+	defer u.Close()
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	//the repeated Run calls and the Run right after Stop (the stopped cycle still sleeps) don't start the second cycle
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				u.Run()
+			}
+		}()
+	}
+	wg.Wait()
+	u.Stop()
+	u.Run()
+	u.FastRun()
+	u.Stop()
+	u.Run()
+	start := u.Status().IterationNum
+	time.Sleep(o.Interval * 10)
+	if steps := u.Status().IterationNum - start; steps > 12 {
+		t.Errorf("got %v steps in %v, want at most 12", steps, o.Interval*10)
+	}
+	//the cycle is over before the universe is closed
+	u.Stop()
+	time.Sleep(o.Interval * 2)
+}
+
+func TestBaseUniverse_LeaveFinished(t *testing.T) {
+	//finished returns the universe finished by the extinction of the single cell
+	finished := func(t *testing.T) *BaseUniverse {
+		o := newUniverseOptions()
+		o.MaxSteps = 0
+		u := newBaseUniverse(o, nil)
+		u.Settle([][]int{{5, 5}})
+		u.StepSync()
+		if st := u.Status(); st.RunningMode != RunningStateFinished || st.FinishReason != FinishReasonExtinct {
+			t.Fatalf("got mode %v (%q), want finished by extinction", st.RunningMode, st.FinishReason)
+		}
+		return u
+	}
+	blinker := []Point{{4, 5}, {5, 5}, {6, 5}}
+	t.Run("edit", func(t *testing.T) {
+		u := finished(t)
+		defer u.Close()
+		u.SetCells(blinker, true)
+		if st := u.Status(); st.RunningMode != RunningStateManual || st.FinishReason != FinishReasonNone {
+			t.Errorf("got mode %v (%q) after the edit, want manual", st.RunningMode, st.FinishReason)
+		}
+		u.StepSync()
+		if st := u.Status(); st.RunningMode != RunningStateManual || st.IterationNum != 2 || st.LiveCells != 3 {
+			t.Errorf("got mode %v, iteration %v, %v cells after the step, want manual, 2, 3", st.RunningMode, st.IterationNum, st.LiveCells)
+		}
+	})
+	t.Run("step", func(t *testing.T) {
+		u := finished(t)
+		defer u.Close()
+		//the field is still empty so the step finishes the universe again
+		u.StepSync()
+		if st := u.Status(); st.RunningMode != RunningStateFinished || st.IterationNum != 2 {
+			t.Errorf("got mode %v, iteration %v, want finished, 2", st.RunningMode, st.IterationNum)
+		}
+		u.InverseCell(4, 5)
+		u.InverseCell(5, 5)
+		u.InverseCell(6, 5)
+		u.StepSync()
+		if st := u.Status(); st.RunningMode != RunningStateManual || st.IterationNum != 3 {
+			t.Errorf("got mode %v, iteration %v, want manual, 3", st.RunningMode, st.IterationNum)
+		}
+	})
+	t.Run("run", func(t *testing.T) {
+		u := finished(t)
+		defer u.Close()
+		modeCh := u.SubscribeMode()
+		u.SetCells(blinker, true)
+		u.Run()
+		for mode := range modeCh {
+			if mode == RunningStateRun {
+				break
+			}
+			if mode == RunningStateFinished {
+				t.Fatalf("got finished mode, want running")
+			}
+		}
+		u.Stop()
+		for mode := range modeCh {
+			if mode == RunningStateManual {
+				break
+			}
+		}
+	})
+}
+
+func TestBaseUniverse_SubscribeMode(t *testing.T) {
+	o := newUniverseOptions()
+	o.MaxSteps = 3
+	//the subscription doesn't need the state channel
+	u := newBaseUniverse(o, nil)
+	modeCh := u.SubscribeMode()
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	u.Step()
+	u.Run()
+	//the manual step doesn't change the mode
+	want := []RunningState{RunningStateRun, RunningStateFinished}
+	for i, w := range want {
+		select {
+		case mode := <-modeCh:
+			if mode != w {
+				t.Errorf("change %v: got mode %v, want %v", i, mode, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("change %v: timeout waiting for mode %v", i, w)
+		}
+	}
+	u.Close()
+	for mode := range modeCh {
+		t.Errorf("got mode %v after finish, want none", mode)
+	}
+}
+
+func TestBaseUniverse_Changes(t *testing.T) {
+	stateCh := newStateCh()
+	u := newBaseUniverse(newUniverseOptions(), stateCh)
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //horizontal blinker
+	if born, died := u.Changes(); len(born) != 0 || len(died) != 0 {
+		t.Errorf("got changes %v, %v before the step, want none", born, died)
+	}
+	u.Step()
+	<-stateCh
+	<-stateCh
+	born, died := u.Changes()
+	if want := []Point{{5, 4}, {5, 6}}; !reflect.DeepEqual(born, want) {
+		t.Errorf("born = %v, want %v", born, want)
+	}
+	if want := []Point{{4, 5}, {6, 5}}; !reflect.DeepEqual(died, want) {
+		t.Errorf("died = %v, want %v", died, want)
+	}
+	u.Close()
+}
+
+func TestBaseUniverse_OnMaxSteps(t *testing.T) {
+	glider := [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	t.Run(OnMaxStepsContinue, func(t *testing.T) {
+		o := newUniverseOptions()
+		o.MaxSteps = 3
+		o.Advanced = map[string]interface{}{AdvOnMaxSteps: OnMaxStepsContinue}
+		stateCh := newStateCh()
+		u := newBaseUniverse(o, stateCh)
+		u.Settle(glider)
+		u.Run()
+		for st := range stateCh {
+			if st.RunningMode == RunningStateFinished {
+				t.Fatalf("finished on iteration %v", st.IterationNum)
+			}
+			if st.IterationNum > o.MaxSteps*2 {
+				break
+			}
+		}
+		stopAndWait(u, stateCh)
+		u.Close()
+	})
+	t.Run(OnMaxStepsReset, func(t *testing.T) {
+		o := newUniverseOptions()
+		o.MaxSteps = 4
+		o.Advanced = map[string]interface{}{AdvOnMaxSteps: OnMaxStepsReset}
+		stateCh := newStateCh()
+		u := newBaseUniverse(o, stateCh)
+		u.Settle(glider)
+		seed := u.Area()
+		//capture the area on the first refresh after the reset
+		var reset *Area
+		maxIteration := 0
+		u.RegisterViewer(&testViewer{refresh: func() {
+			st := u.Status()
+			if st.IterationNum > maxIteration {
+				maxIteration = st.IterationNum
+			}
+			if st.IterationNum == 0 && maxIteration > 0 && reset == nil {
+				a := u.Area()
+				reset = &a
+			}
+		}})
+		u.Run()
+		//wait for a few steps after the reset
+		resets, lastIteration := 0, 0
+		for st := range stateCh {
+			if st.RunningMode == RunningStateFinished {
+				t.Fatalf("finished on iteration %v", st.IterationNum)
+			}
+			if st.IterationNum < lastIteration {
+				resets++
+			}
+			lastIteration = st.IterationNum
+			if resets > 0 && st.IterationNum >= 2 {
+				break
+			}
+		}
+		stopAndWait(u, stateCh)
+		if reset == nil {
+			t.Fatal("the universe was not reset")
+		}
+		if d, _ := Diff(seed, *reset); len(d) != 0 {
+			t.Errorf("the area differs from the seed after the reset in %v", d)
+		}
+		if maxIteration != o.MaxSteps-1 {
+			t.Errorf("got max iteration %v, want %v", maxIteration, o.MaxSteps-1)
+		}
+		u.Close()
+	})
+}
+
+//testViewer is the Viewer calling refresh on each universe change
+type testViewer struct {
+	refresh func()
+}
+
+func (v *testViewer) Refresh() {
+	if v.refresh != nil {
+		v.refresh()
+	}
+}
+
+func (v *testViewer) Register(_ *BaseUniverse) {}
+
+func (v *testViewer) Start() {}
+
+//waitMode reads the states until the universe is switched to the mode
+func waitMode(stateCh chan Status, mode RunningState) Status {
+	for st := range stateCh {
+		if st.RunningMode == mode {
+			return st
+		}
+	}
+	return Status{}
+}
+
+//stopAndWait stops the running universe and reads the states until the universe is paused
+func stopAndWait(u Universe, stateCh chan Status) {
+	go u.Stop()
+	waitMode(stateCh, RunningStateManual)
+}
+
+func TestBaseUniverse_SetCells(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 5, 5
+	u := newBaseUniverse(o, newStateCh())
+	defer u.Close()
+	//the points outside the area are ignored
+	u.SetCells([]Point{{0, 0}, {4, 4}, {-1, 2}, {5, 0}, {2, 7}}, true)
+	u.SetCell(2, 2, true)
+	u.SetCell(4, 4, false)
+	if d, _ := Diff(newTestArea(5, 5, [][]int{{0, 0}, {2, 2}}), u.Area()); len(d) != 0 {
+		t.Errorf("the area differs in %v", d)
+	}
+}
+
+func TestBaseUniverse_InvertAll(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 3, 2
+	u := newBaseUniverse(o, nil)
+	defer u.Close()
+	u.SetCells([]Point{{0, 0}, {2, 1}}, true)
+	u.InvertAll()
+	if d, _ := Diff(newTestArea(3, 2, [][]int{{1, 0}, {2, 0}, {0, 1}, {1, 1}}), u.Area()); len(d) != 0 {
+		t.Errorf("the area differs in %v", d)
+	}
+	if st := u.Status(); st.LiveCells != 4 {
+		t.Errorf("got %v live cells, want 4", st.LiveCells)
+	}
+	u.InvertAll()
+	if st := u.Status(); st.LiveCells != 2 {
+		t.Errorf("got %v live cells after the second inversion, want 2", st.LiveCells)
+	}
+}
+
+func TestBaseUniverse_SettleWithRandomDataLifespan(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 40, 30
+	u := newBaseUniverse(o, nil)
+	defer u.Close()
+	//the field is settled when the call returns, so the lifespan is analyzed for the random field
+	u.SettleWithRandomData()
+	a := u.Area()
+	initial := 0
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			if a.Entities[y][x] {
+				initial++
+			}
+		}
+	}
+	if initial == 0 {
+		t.Fatal("the random field is empty")
+	}
+	if st := u.Status(); st.LiveCells != initial {
+		t.Errorf("got %v live cells in the status, want %v", st.LiveCells, initial)
+	}
+	if l := AnalyzeLifespan(a, u.Rule(), u.Boundary(), 0); l.PeakPopulation < initial {
+		t.Errorf("got the peak population %v, want at least the initial %v", l.PeakPopulation, initial)
+	}
+}
+
+func TestEngines_SetArea(t *testing.T) {
+	for _, name := range engineNames() {
+		t.Run(name, func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height, o.MaxSteps = 5, 5, 0
+			u := engines[name](o, nil)
+			defer u.Close()
+			blinker := newTestArea(5, 5, [][]int{{1, 2}, {2, 2}, {3, 2}})
+			if err := u.SetArea(blinker); err != nil {
+				t.Fatal(err)
+			}
+			got := u.Area()
+			if !reflect.DeepEqual(got, blinker) {
+				t.Errorf("got %v, want %v", got.Entities, blinker.Entities)
+			}
+			//the field is the copy of the area
+			blinker.Entities[0][0] = true
+			if u.Area().Entities[0][0] {
+				t.Error("the field is changed with the area")
+			}
+			if st := u.Status(); st.LiveCells != 3 {
+				t.Errorf("got %v live cells, want 3", st.LiveCells)
+			}
+			u.StepSync()
+			if d, _ := Diff(newTestArea(5, 5, [][]int{{2, 1}, {2, 2}, {2, 3}}), u.Area()); len(d) != 0 {
+				t.Errorf("the stepped area differs in %v", d)
+			}
+			if err := u.SetArea(newTestArea(5, 4, nil)); !errors.Is(err, ErrAreaSizeMismatch) {
+				t.Errorf("got error %v, want %v", err, ErrAreaSizeMismatch)
+			}
+			if err := u.SetArea(Area{Width: 5, Height: 5, Entities: make([][]Cell, 5)}); !errors.Is(err, ErrAreaSizeMismatch) {
+				t.Errorf("got error %v for the malformed area, want %v", err, ErrAreaSizeMismatch)
+			}
+		})
+	}
+}
+
+func TestEngines_StepWithin(t *testing.T) {
+	glider := [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	for _, name := range engineNames() {
+		t.Run(name, func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height, o.MaxSteps = 8, 8, 0
+			o.Advanced = map[string]interface{}{AdvBoundary: string(BoundaryTorus)}
+			u := engines[name](o, nil)
+			defer u.Close()
+			u.Settle(glider)
+			//the whole generation is done even if it doesn't fit the budget
+			if n := u.StepWithin(0); n != 1 {
+				t.Errorf("got %d steps within no budget, want 1", n)
+			}
+			n := u.StepWithin(20 * time.Millisecond)
+			if n < 1 {
+				t.Errorf("got %d steps within the budget, want at least 1", n)
+			}
+			if st := u.Status(); st.IterationNum != n+1 || st.RunningMode != RunningStateManual {
+				t.Errorf("got step %d in mode %v, want step %d in manual mode", st.IterationNum, st.RunningMode, n+1)
+			}
+			if st := u.Status(); st.LiveCells != 5 {
+				t.Errorf("got %d live cells, want the glider", st.LiveCells)
+			}
+		})
+	}
+}
+
+func TestBaseUniverse_StepWithinFinished(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height, o.MaxSteps = 8, 8, 5
+	u := newBaseUniverse(o, nil)
+	defer u.Close()
+	u.Settle([][]int{{1, 2}, {2, 2}, {3, 2}})
+	//the steps are stopped by the finish long before the budget is spent
+	if n := u.StepWithin(time.Hour); n != 5 {
+		t.Errorf("got %d steps, want 5", n)
+	}
+	if st := u.Status(); st.RunningMode != RunningStateFinished || st.FinishReason != FinishReasonMaxSteps {
+		t.Errorf("got mode %v with reason %q, want finished by the steps limit", st.RunningMode, st.FinishReason)
+	}
+}
+
+func TestBaseUniverse_Iterate(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height, o.MaxSteps = 8, 8, 5
+	u := newBaseUniverse(o, nil)
+	defer u.Close()
+	u.Settle([][]int{{1, 2}, {2, 2}, {3, 2}})
+	//the iteration is stopped by fn
+	var gens []int
+	u.Iterate(10, func(gen int, a Area) bool {
+		gens = append(gens, gen)
+		//the area is the copy
+		a.Entities[0][0] = true
+		return gen < 2
+	})
+	if !reflect.DeepEqual(gens, []int{1, 2}) || bool(u.Area().Entities[0][0]) {
+		t.Errorf("got generations %v, want [1 2] with the field untouched", gens)
+	}
+	//the iteration is stopped by the steps limit
+	gens = nil
+	u.Iterate(10, func(gen int, _ Area) bool {
+		gens = append(gens, gen)
+		return true
+	})
+	if !reflect.DeepEqual(gens, []int{3, 4, 5}) {
+		t.Errorf("got generations %v, want [3 4 5]", gens)
+	}
+	u.Iterate(10, func(gen int, _ Area) bool {
+		t.Errorf("got generation %v of the finished universe", gen)
+		return true
+	})
+}
+
+func TestBaseUniverse_Source(t *testing.T) {
+	stateCh := newStateCh()
+	u := newBaseUniverse(newUniverseOptions(), stateCh)
+	defer u.Close()
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	blinker := Source{Name: "Blinker", Path: "patterns/blinker.rle"}
+	u.SetSource(blinker)
+	u.Step()
+	waitMode(stateCh, RunningStateManual)
+	if s := u.Source(); s != blinker {
+		t.Errorf("got source %v after the step, want %v", s, blinker)
+	}
+	u.SetCell(1, 1, true)
+	if s := u.Source(); s != (Source{}) {
+		t.Errorf("got source %v after drawing, want none", s)
+	}
+	u.SetSource(blinker)
+	u.Clear()
+	waitMode(stateCh, RunningStateManual)
+	if s := u.Source(); s != (Source{}) {
+		t.Errorf("got source %v after clearing, want none", s)
+	}
+}
+
+func TestBaseUniverse_FastRunStop(t *testing.T) {
+	o := newUniverseOptions()
+	o.MaxSteps = 0
+	stateCh := newStateCh()
+	u := newBaseUniverse(o, stateCh)
+	defer u.Close()
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	refreshed := make(chan Status, 10)
+	u.RegisterViewer(&testViewer{refresh: func() {
+		refreshed <- u.Status()
+	}})
+	u.FastRun()
+	if st := <-refreshed; !st.FastRun {
+		t.Fatal("the views are not informed about the fast run")
+	}
+	waitMode(stateCh, RunningStateRun)
+	stopAndWait(u, stateCh)
+	//the views are refreshed once the fast run is stopped
+	select {
+	case st := <-refreshed:
+		if st.FastRun || st.RunningMode != RunningStateManual {
+			t.Errorf("got fast run %v in mode %v, want manual mode", st.FastRun, st.RunningMode)
+		}
+	case <-time.After(time.Second):
+		t.Error("the views are not refreshed after the fast run")
+	}
+}
+
+func TestBaseUniverse_AreaCopy(t *testing.T) {
+	for _, e := range engineNames() {
+		t.Run(e, func(t *testing.T) {
+			u := engines[e](newUniverseOptions(), newStateCh())
+			defer u.Close()
+			u.Settle(testTemplate.Coordinates)
+			want := u.Area()
+			//mutating the returned area doesn't affect the universe
+			a := u.Area()
+			for y := range a.Entities {
+				for x := range a.Entities[y] {
+					a.Entities[y][x] = !a.Entities[y][x]
+				}
+			}
+			a.Entities[0] = nil
+			if d, err := Diff(want, u.Area()); err != nil || len(d) != 0 {
+				t.Errorf("the universe area is changed in %v cells (%v)", len(d), err)
+			}
+		})
+	}
+}
+
+func TestEngines_SameFields(t *testing.T) {
+	seed := RandomArea(width, height, DefDensity, 1)
+	points := make([]Point, 0)
+	for y := range seed.Entities {
+		for x := range seed.Entities[y] {
+			if seed.Entities[y][x] {
+				points = append(points, Point{x, y})
+			}
+		}
+	}
+	for _, b := range []Boundary{BoundaryNone, BoundaryTorus} {
+		want := seed
+		for i := 0; i < 20; i++ {
+			want = nextGeneration(want, ConwayRule, b)
+		}
+		for _, name := range engineNames() {
+			o := newUniverseOptions()
+			o.Advanced = map[string]interface{}{AdvBoundary: string(b)}
+			u := engines[name](o, nil)
+			u.SetCells(points, true)
+			for i := 0; i < 20; i++ {
+				u.StepSync()
+			}
+			if d, _ := Diff(want, u.Area()); len(d) != 0 {
+				t.Errorf("%s, %s: the field differs from the serial calculation in %v cells, the first is %v", name, b, len(d), d[0])
+			}
+			u.Close()
+		}
+	}
+}
+
+//TestEngines_StatusWhileRunning reads the status while the engines step and change the field, run it with -race
+func TestEngines_StatusWhileRunning(t *testing.T) {
+	for _, e := range engineNames() {
+		t.Run(e, func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height, o.MaxSteps = 40, 30, 0
+			u := engines[e](o, nil)
+			defer u.Close()
+			u.SettleWithRandomData()
+			stop := make(chan bool)
+			read := make(chan int)
+			go func() {
+				reads := 0
+				for {
+					select {
+					case <-stop:
+						read <- reads
+						return
+					default:
+					}
+					st := u.Status()
+					_, _ = st.LiveCells, st.IterationTime
+					reads++
+				}
+			}()
+			u.Run()
+			deadline := time.Now().Add(5 * time.Second)
+			for u.Status().IterationNum < 20 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			u.InvertAll()
+			u.SetCells([]Point{{1, 1}}, true)
+			u.Stop()
+			close(stop)
+			if n := <-read; n == 0 {
+				t.Error("the status is not read")
+			}
+			if st := u.Status(); st.IterationNum < 20 {
+				t.Errorf("got %v steps, want at least 20", st.IterationNum)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package universe
+
+import (
+	"sort"
+	"time"
+)
+
+//BenchResult is the speed and the outcome of the engine run by BenchEngines
+type BenchResult struct {
+	Engine          string
+	Steps           int
+	Duration        time.Duration //the time of the steps, the seeding is not counted
+	StepsPerSec     float64
+	FinalPopulation int
+	Differs         int //the count of the cells differing from the serial calculation, 0 if the engine matches
+}
+
+//BenchEngines runs the random area of the seed for steps generations on each engine and measures the speed
+//the dimensions, the rule and the boundary are taken from the options, the density from the AdvDensity option (SuggestedDensity of the rule if unset)
+//the final fields are compared with the serial calculation, the results are sorted by the engine name
+func BenchEngines(engines map[string]func(o *Options, stateCh chan Status) (Universe, error), o *Options, seed int64, steps int) ([]BenchResult, error) {
+	if o == nil {
+		o = &DefaultUniverseOptions
+	}
+	//the universe is used to read the advanced options only
+	u := BaseUniverse{options: *o}
+	rule := ConwayRule
+	if r, err := ParseRule(u.advancedString(AdvRule, "")); err == nil {
+		rule = r
+	}
+	boundary, _ := ParseBoundary(u.advancedString(AdvBoundary, ""))
+	density, ok := o.Advanced[AdvDensity].(float64)
+	if !ok {
+		density = SuggestedDensity(rule)
+	}
+
+	seedArea := RandomArea(o.Width, o.Height, density, seed)
+	points := make([]Point, 0)
+	for y := range seedArea.Entities {
+		for x := range seedArea.Entities[y] {
+			if seedArea.Entities[y][x] {
+				points = append(points, Point{x, y})
+			}
+		}
+	}
+	want := seedArea
+	for i := 0; i < steps; i++ {
+		want = nextGeneration(want, rule, boundary)
+	}
+
+	names := make([]string, 0, len(engines))
+	for name := range engines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	results := make([]BenchResult, 0, len(names))
+	for _, name := range names {
+		eo := *o
+		//the engines are stepped up to the end regardless of the limits
+		eo.MaxSteps = 0
+		//the history copies are not measured
+		eo.Advanced = make(map[string]interface{}, len(o.Advanced)+1)
+		for k, v := range o.Advanced {
+			eo.Advanced[k] = v
+		}
+		eo.Advanced[AdvHistory] = 0
+		eu, err := engines[name](&eo, nil)
+		if err != nil {
+			return results, err
+		}
+		eu.SetCells(points, true)
+		start := time.Now()
+		for i := 0; i < steps; i++ {
+			eu.StepSync()
+		}
+		res := BenchResult{Engine: name, Steps: steps, Duration: time.Since(start)}
+		if res.Duration > 0 {
+			res.StepsPerSec = float64(steps) / res.Duration.Seconds()
+		}
+		a := eu.Area()
+		eu.Close()
+		res.FinalPopulation = population(a)
+		d, err := Diff(want, a)
+		if err != nil {
+			return results, err
+		}
+		res.Differs = len(d)
+		results = append(results, res)
+	}
+	return results, nil
+}
@@ -0,0 +1,41 @@
+package universe
+
+import (
+	"testing"
+)
+
+//benchEngines returns the test engines in the form accepted by BenchEngines
+func benchEngines() map[string]func(o *Options, stateCh chan Status) (Universe, error) {
+	m := make(map[string]func(o *Options, stateCh chan Status) (Universe, error), len(engines))
+	for name, newUniverse := range engines {
+		newUniverse := newUniverse
+		m[name] = func(o *Options, stateCh chan Status) (Universe, error) {
+			return newUniverse(o, stateCh), nil
+		}
+	}
+	return m
+}
+
+func TestBenchEngines(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height, o.MaxSteps = 64, 64, 5
+	o.Advanced = map[string]interface{}{AdvBoundary: string(BoundaryTorus)}
+	results, err := BenchEngines(benchEngines(), o, 3, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(engines) {
+		t.Fatalf("got %d results, want %d", len(results), len(engines))
+	}
+	for i, r := range results {
+		if i > 0 && r.Engine < results[i-1].Engine {
+			t.Errorf("the results are not sorted at %d", i)
+		}
+		if r.Steps != 30 || r.Differs != 0 {
+			t.Errorf("%s: got %d steps and %d differing cells, want 30 steps matching the serial calculation", r.Engine, r.Steps, r.Differs)
+		}
+		if r.FinalPopulation != results[0].FinalPopulation {
+			t.Errorf("%s: got final population %d, want %d", r.Engine, r.FinalPopulation, results[0].FinalPopulation)
+		}
+	}
+}
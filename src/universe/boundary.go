@@ -0,0 +1,34 @@
+package universe
+
+//Boundary defines how the area edges behave
+type Boundary string
+
+const (
+	BoundaryNone  Boundary = "none"   //the cells outside the area are always dead
+	BoundaryWrapX Boundary = "wrap-x" //the left and right edges are connected
+	BoundaryWrapY Boundary = "wrap-y" //the top and bottom edges are connected
+	BoundaryTorus Boundary = "torus"  //both pairs of the edges are connected
+)
+
+//Boundaries is the list of all the boundary modes
+var Boundaries = []Boundary{BoundaryNone, BoundaryWrapX, BoundaryWrapY, BoundaryTorus}
+
+//ParseBoundary returns the boundary by its name, ok is false for the unknown names
+func ParseBoundary(s string) (b Boundary, ok bool) {
+	for _, b := range Boundaries {
+		if string(b) == s {
+			return b, true
+		}
+	}
+	return BoundaryNone, false
+}
+
+//WrapX checks if the left and right edges are connected
+func (b Boundary) WrapX() bool {
+	return b == BoundaryWrapX || b == BoundaryTorus
+}
+
+//WrapY checks if the top and bottom edges are connected
+func (b Boundary) WrapY() bool {
+	return b == BoundaryWrapY || b == BoundaryTorus
+}
@@ -0,0 +1,53 @@
+package universe
+
+import (
+	"testing"
+)
+
+func TestBaseUniverse_Boundary(t *testing.T) {
+	//the vertical blinker on the left edge
+	blinker := [][]int{{0, 4}, {0, 5}, {0, 6}}
+	tests := []struct {
+		boundary Boundary
+		want     []Point
+	}{
+		{BoundaryNone, []Point{{0, 5}, {1, 5}}},
+		{BoundaryWrapX, []Point{{0, 5}, {1, 5}, {9, 5}}},
+		{BoundaryWrapY, []Point{{0, 5}, {1, 5}}},
+		{BoundaryTorus, []Point{{0, 5}, {1, 5}, {9, 5}}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.boundary), func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height = 10, 10
+			o.Advanced = map[string]interface{}{AdvBoundary: string(tt.boundary)}
+			stateCh := newStateCh()
+			u := newBaseUniverse(o, stateCh)
+			u.Settle(blinker)
+			u.Step()
+			waitMode(stateCh, RunningStateManual)
+			got := make([]Point, 0)
+			a := u.Area()
+			for y := range a.Entities {
+				for x := range a.Entities[y] {
+					if a.Entities[y][x] {
+						got = append(got, Point{x, y})
+					}
+				}
+			}
+			if d, _ := Diff(newTestArea(10, 10, pointsToCoordinates(tt.want)), a); len(d) != 0 {
+				t.Errorf("got live cells %v, want %v", got, tt.want)
+			}
+			u.Close()
+		})
+	}
+}
+
+//pointsToCoordinates converts the points to the array of x,y coordinates
+func pointsToCoordinates(points []Point) [][]int {
+	vc := make([][]int, len(points))
+	for i, p := range points {
+		vc[i] = []int{p.X, p.Y}
+	}
+	return vc
+}
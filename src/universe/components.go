@@ -0,0 +1,61 @@
+package universe
+
+//Connectivity defines which cells are considered as neighbours when the live cells clusters are searched
+type Connectivity int
+
+const (
+	Connectivity4 Connectivity = 4 //orthogonal neighbours only
+	Connectivity8 Connectivity = 8 //orthogonal and diagonal neighbours
+)
+
+var (
+	neighbours4 = [][]int{{0, -1}, {-1, 0}, {1, 0}, {0, 1}}
+	neighbours8 = [][]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+)
+
+//Components finds the connected clusters of the live cells, the cells across the wrapped edges of the boundary are neighbours
+//returns the sizes of the clusters in order of their top-left cell, the clusters count is the length of the result
+//the computation walks the entire area, so it should be called on demand rather than on each step
+func Components(a Area, c Connectivity, b Boundary) []int {
+	offsets := neighbours8
+	if c == Connectivity4 {
+		offsets = neighbours4
+	}
+	sizes := make([]int, 0)
+	visited := createArea(a.Width, a.Height)
+	stack := make([][]int, 0)
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			if !a.Entities[y][x] || visited.Entities[y][x] {
+				continue
+			}
+			//flood fill the cluster started from x,y
+			size := 0
+			visited.Entities[y][x] = true
+			stack = append(stack[:0], []int{x, y})
+			for len(stack) > 0 {
+				p := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				size++
+				for _, o := range offsets {
+					nx, ny := p[0]+o[0], p[1]+o[1]
+					if b.WrapX() {
+						nx = (nx + a.Width) % a.Width
+					}
+					if b.WrapY() {
+						ny = (ny + a.Height) % a.Height
+					}
+					if nx < 0 || ny < 0 || nx >= a.Width || ny >= a.Height {
+						continue
+					}
+					if a.Entities[ny][nx] && !visited.Entities[ny][nx] {
+						visited.Entities[ny][nx] = true
+						stack = append(stack, []int{nx, ny})
+					}
+				}
+			}
+			sizes = append(sizes, size)
+		}
+	}
+	return sizes
+}
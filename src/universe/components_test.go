@@ -0,0 +1,49 @@
+package universe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComponents(t *testing.T) {
+	a := createArea(6, 5)
+	//two diagonal cells, an L-shape and a single cell
+	for _, c := range [][]int{{0, 0}, {1, 1}, {4, 0}, {4, 1}, {5, 1}, {2, 4}} {
+		a.Entities[c[1]][c[0]] = true
+	}
+	tests := []struct {
+		c    Connectivity
+		want []int
+	}{
+		{Connectivity8, []int{2, 3, 1}},
+		{Connectivity4, []int{1, 3, 1, 1}},
+	}
+	for _, tt := range tests {
+		if got := Components(a, tt.c, BoundaryNone); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Components(%v) = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+	if got := Components(createArea(3, 3), Connectivity8, BoundaryNone); len(got) != 0 {
+		t.Errorf("Components of the empty area = %v, want none", got)
+	}
+}
+
+func TestComponents_Wrapped(t *testing.T) {
+	//the cells in the opposite corners touch across the wrapped edges
+	a := newTestArea(5, 4, [][]int{{0, 0}, {4, 0}, {4, 3}, {2, 2}})
+	tests := []struct {
+		b    Boundary
+		c    Connectivity
+		want []int
+	}{
+		{BoundaryNone, Connectivity8, []int{1, 1, 1, 1}},
+		{BoundaryWrapX, Connectivity4, []int{2, 1, 1}},
+		{BoundaryWrapY, Connectivity4, []int{1, 2, 1}},
+		{BoundaryTorus, Connectivity8, []int{3, 1}},
+	}
+	for _, tt := range tests {
+		if got := Components(a, tt.c, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Components(%v, %v) = %v, want %v", tt.c, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//Package universe is the "The Life" game simulation engine, it doesn't depend on the views and can be embedded
+//
+//The universe is created by NewBaseUniverse or by the other engine constructors (NewSimpleUniverse, NewSmallBuffUniverse,
+//NewMultithreadedUniverse, NewHashlifeUniverse), all of them implement the Universe interface.
+//The field is settled by Settle, SettleLayout or InsertPattern, the patterns are read by LoadRLE and LoadLayout
+//and written by SaveRLE, RandomArea creates the reproducible random field.
+//The simulation is advanced by Step, Run and FastRun, Area and Status return the current field and counters.
+//
+//The next generation is calculated from the previous one only, the new states are never read during the step,
+//so the engines give the same fields whatever order the cells are visited in: the base engine walks the field
+//in the row-major order, the multithreaded one calculates the row bands in parallel.
+//The rules are deterministic, the only random source is the seed given to RandomArea.
+//
+//The commands are executed by the universe goroutine one by one, so the universe can be controlled from any goroutine.
+//The state changes are sent to the status channel given to the constructor (if any),
+//the registered viewers are refreshed on every change. Close stops the universe goroutine.
+package universe
@@ -0,0 +1,67 @@
+package universe
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+var (
+	ErrInvalidEncoding = errors.New("invalid area encoding")
+)
+
+//EncodeArea returns the compact URL-safe text of the area: "WxH." followed by the deflated cell bits in the unpadded base64url
+//the cells are packed row by row, the lowest bit first
+func EncodeArea(a Area) string {
+	bits := make([]byte, (a.Width*a.Height+7)/8)
+	i := 0
+	for y := range a.Entities {
+		for _, c := range a.Entities[y] {
+			if c {
+				bits[i/8] |= 1 << uint(i%8)
+			}
+			i++
+		}
+	}
+	var b bytes.Buffer
+	w, _ := flate.NewWriter(&b, flate.BestCompression)
+	_, _ = w.Write(bits)
+	_ = w.Close()
+	return fmt.Sprintf("%dx%d.%s", a.Width, a.Height, base64.RawURLEncoding.EncodeToString(b.Bytes()))
+}
+
+//DecodeArea restores the area encoded by EncodeArea
+func DecodeArea(s string) (Area, error) {
+	parts := strings.SplitN(s, ".", 2)
+	if len(parts) != 2 {
+		return Area{}, fmt.Errorf("%w: the dimensions are missing", ErrInvalidEncoding)
+	}
+	var width, height int
+	if n, _ := fmt.Sscanf(parts[0], "%dx%d", &width, &height); n != 2 || width < 0 || height < 0 {
+		return Area{}, fmt.Errorf("%w: malformed dimensions %q", ErrInvalidEncoding, parts[0])
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Area{}, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	bits, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return Area{}, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	if len(bits) != (width*height+7)/8 {
+		return Area{}, fmt.Errorf("%w: got %d bytes of cells for %dx%d area", ErrInvalidEncoding, len(bits), width, height)
+	}
+	a := createArea(width, height)
+	i := 0
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			a.Entities[y][x] = Cell(bits[i/8]&(1<<uint(i%8)) != 0)
+			i++
+		}
+	}
+	return a, nil
+}
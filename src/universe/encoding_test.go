@@ -0,0 +1,33 @@
+package universe
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeArea(t *testing.T) {
+	for _, a := range []Area{RandomArea(37, 11, 0.3, 5), createArea(3, 3), createArea(0, 0)} {
+		s := EncodeArea(a)
+		if strings.ContainsAny(s, "+/=") {
+			t.Errorf("the encoding %q is not URL-safe", s)
+		}
+		got, err := DecodeArea(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, a) {
+			t.Errorf("%vx%v: the area differs after the round trip", a.Width, a.Height)
+		}
+	}
+	if s := EncodeArea(createArea(200, 200)); len(s) > 100 {
+		t.Errorf("got %d chars for the empty area, want it compact", len(s))
+	}
+	valid := EncodeArea(RandomArea(8, 8, 0.5, 1))
+	for _, s := range []string{"", "8x8", "8y8." + valid[4:], "8x9." + valid[4:], "8x8.!!!"} {
+		if _, err := DecodeArea(s); !errors.Is(err, ErrInvalidEncoding) {
+			t.Errorf("%q: got error %v, want %v", s, err, ErrInvalidEncoding)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package universe_test
+
+import (
+	"fmt"
+	"os"
+	"simlife/src/universe"
+)
+
+func ExampleNewBaseUniverse() {
+	o := universe.DefaultUniverseOptions
+	o.Width, o.Height = 10, 10
+	stateCh := make(chan universe.Status, 10)
+	u, err := universe.NewBaseUniverse(&o, stateCh)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer u.Close()
+
+	glider := universe.Template{Name: "glider", Coordinates: [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}}
+	u.InsertPattern(glider, 1, 1, universe.AnchorTopLeft)
+	for i := 0; i < 4; i++ {
+		u.Step()
+		//the step is done when the universe is back to the manual mode
+		for st := range stateCh {
+			if st.RunningMode == universe.RunningStateManual {
+				break
+			}
+		}
+	}
+	st := u.Status()
+	fmt.Println("step:", st.IterationNum, "live cells:", st.LiveCells)
+	_ = universe.SaveRLE(os.Stdout, u.Area(), u.Rule(), u.Boundary())
+	// Output:
+	// step: 4 live cells: 5
+	// x = 3, y = 3, rule = B3/S23:P10,10
+	// bo$2bo$3o!
+}
+
+func ExampleBaseUniverse_Iterate() {
+	o := universe.DefaultUniverseOptions
+	o.Width, o.Height = 10, 10
+	u, err := universe.NewBaseUniverse(&o, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer u.Close()
+
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	u.Iterate(3, func(gen int, a universe.Area) bool {
+		min, max, _ := universe.LiveBounds(a)
+		fmt.Println("step:", gen, "bounds:", min, max)
+		return true
+	})
+	// Output:
+	// step: 1 bounds: {5 4} {5 6}
+	// step: 2 bounds: {4 5} {6 5}
+	// step: 3 bounds: {5 4} {5 6}
+}
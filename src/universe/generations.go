@@ -0,0 +1,131 @@
+package universe
+
+import "errors"
+
+//DefHistorySize is the count of the past generations the console UI keeps for scrubbing
+//the universe keeps no history unless AdvHistory is set, so the headless runs don't copy the area twice a step
+const DefHistorySize = 100
+
+//DefHistoryMB is the megabytes the past generations take at most when AdvHistoryMB is not set
+const DefHistoryMB = 256
+
+var (
+	ErrGenerationNotKept = errors.New("the generation is not kept in the history")
+)
+
+//generationHistory is the areas of the consecutive generations since the last change by hand, the oldest first
+//the areas are the published snapshots, they are never changed
+type generationHistory struct {
+	first int //the generation of areas[0]
+	areas []Area
+	bytes int //the estimated size of the areas
+}
+
+//push adds the area of the generation dropping the oldest ones beyond size or taking more than maxBytes
+//maxBytes 0 doesn't limit the bytes, the history is restarted on the gap
+func (gh *generationHistory) push(generation int, a Area, size int, maxBytes int) {
+	if size <= 0 {
+		*gh = generationHistory{}
+		return
+	}
+	if len(gh.areas) == 0 || generation != gh.first+len(gh.areas) {
+		*gh = generationHistory{first: generation}
+	}
+	gh.areas = append(gh.areas, a)
+	gh.bytes += areaBytes(a)
+	for len(gh.areas) > size || maxBytes > 0 && gh.bytes > maxBytes && len(gh.areas) > 0 {
+		gh.bytes -= areaBytes(gh.areas[0])
+		gh.first++
+		gh.areas = gh.areas[1:]
+	}
+}
+
+//get returns the area of the generation, ok is false if it's not kept
+func (gh *generationHistory) get(generation int) (a Area, ok bool) {
+	i := generation - gh.first
+	if i < 0 || i >= len(gh.areas) {
+		return Area{}, false
+	}
+	return gh.areas[i], true
+}
+
+//truncate drops the generations after the one given
+func (gh *generationHistory) truncate(generation int) {
+	if i := generation - gh.first + 1; i >= 0 && i < len(gh.areas) {
+		for _, a := range gh.areas[i:] {
+			gh.bytes -= areaBytes(a)
+		}
+		gh.areas = gh.areas[:i]
+	}
+}
+
+//History returns the range of the generations kept in the history, ok is false if none is kept
+//the history is restarted when the field is changed not by a step
+func (u *BaseUniverse) History() (first int, last int, ok bool) {
+	u.area.Lock()
+	defer u.area.Unlock()
+	if len(u.generations.areas) == 0 {
+		return 0, 0, false
+	}
+	return u.generations.first, u.generations.first + len(u.generations.areas) - 1, true
+}
+
+//Generation returns the copy of the generation kept in the history, ok is false if it's not kept
+//the field isn't changed, so the past generations can be viewed while the simulation goes on
+func (u *BaseUniverse) Generation(n int) (a Area, ok bool) {
+	u.area.Lock()
+	a, ok = u.generations.get(n)
+	u.area.Unlock()
+	if !ok {
+		return Area{}, false
+	}
+	return copyArea(a), true
+}
+
+//Fork stops the simulation and restores the generation n kept in the history, returns when it's restored
+//the later generations are dropped from the history, the next step continues from n
+//returns ErrGenerationNotKept if the generation is not kept, nothing is changed in this case
+func (u *BaseUniverse) Fork(n int) error {
+	errCh := make(chan error)
+	u.controlCh <- func() {
+		u.area.Lock()
+		_, ok := u.generations.get(n)
+		u.area.Unlock()
+		if !ok {
+			errCh <- ErrGenerationNotKept
+			return
+		}
+		u.stop()
+		u.area.Lock()
+		a, ok := u.generations.get(n)
+		if !ok {
+			//the field is changed by hand meanwhile
+			u.area.Unlock()
+			errCh <- ErrGenerationNotKept
+			return
+		}
+		u.walkArea(func(x int, y int, _ Cell) {
+			color := uint8(1)
+			if a.Colors != nil {
+				color = a.Colors[y][x]
+			}
+			u.setCell(x, y, bool(a.Entities[y][x]), color)
+		})
+		u.history = nil
+		u.generations.truncate(n)
+		u.snapshot.Store(areaSnapshot{area: a})
+		u.area.Unlock()
+		live := u.liveCells()
+		u.state.Lock()
+		u.state.IterationNum = n
+		u.state.LiveCells = live
+		u.state.FinishReason = FinishReasonNone
+		u.state.Period = 0
+		u.state.Components = nil
+		u.state.Unlock()
+		u.reactivate()
+		u.refreshView()
+		errCh <- nil
+	}
+	return <-errCh
+}
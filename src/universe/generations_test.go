@@ -0,0 +1,127 @@
+package universe
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGenerationHistory(t *testing.T) {
+	var gh generationHistory
+	areas := make([]Area, 6)
+	for i := range areas {
+		areas[i] = Area{Width: i}
+		gh.push(i, areas[i], 4, 0)
+	}
+	//the oldest generations are dropped beyond the size
+	if gh.first != 2 || len(gh.areas) != 4 {
+		t.Fatalf("got generations %v-%v, want 2-5", gh.first, gh.first+len(gh.areas)-1)
+	}
+	if _, ok := gh.get(1); ok {
+		t.Error("got the dropped generation 1")
+	}
+	if a, ok := gh.get(3); !ok || a.Width != 3 {
+		t.Errorf("got area %v, %v, want generation 3", a.Width, ok)
+	}
+	gh.truncate(3)
+	if _, ok := gh.get(4); ok || len(gh.areas) != 2 {
+		t.Errorf("got %d generations after truncating, want 2", len(gh.areas))
+	}
+	//the gap restarts the history
+	gh.push(10, areas[0], 4, 0)
+	if gh.first != 10 || len(gh.areas) != 1 {
+		t.Errorf("got generations %v-%v after the gap, want 10-10", gh.first, gh.first+len(gh.areas)-1)
+	}
+	gh.push(11, areas[1], 0, 0)
+	if len(gh.areas) != 0 || gh.bytes != 0 {
+		t.Errorf("got %d generations of %d bytes with no history, want none", len(gh.areas), gh.bytes)
+	}
+}
+
+func TestGenerationHistory_MaxBytes(t *testing.T) {
+	var gh generationHistory
+	a := createArea(10, 10)
+	size := areaBytes(a)
+	for i := 0; i < 5; i++ {
+		gh.push(i, a, 10, 3*size)
+	}
+	//the oldest generations are dropped beyond the bytes
+	if gh.first != 2 || len(gh.areas) != 3 || gh.bytes != 3*size {
+		t.Fatalf("got generations %v-%v of %v bytes, want 2-4 of %v", gh.first, gh.first+len(gh.areas)-1, gh.bytes, 3*size)
+	}
+	gh.truncate(2)
+	if gh.bytes != size {
+		t.Errorf("got %v bytes after truncating, want %v", gh.bytes, size)
+	}
+}
+
+func TestBaseUniverse_HistoryMemory(t *testing.T) {
+	for _, size := range []int{0, 4} {
+		o := newUniverseOptions()
+		o.Width, o.Height, o.MaxSteps = 10, 10, 0
+		o.Advanced = map[string]interface{}{AdvHistory: size}
+		u := newBaseUniverse(o, nil)
+		u.Settle([][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}) //glider
+		for i := 0; i < 6; i++ {
+			u.StepSync()
+		}
+		st := u.Status()
+		if want := size * areaBytes(u.Area()); st.HistoryMemory != want {
+			t.Errorf("history %v: got %v history bytes, want %v", size, st.HistoryMemory, want)
+		}
+		if st.Memory < st.HistoryMemory {
+			t.Errorf("history %v: got memory %v less than the history bytes %v", size, st.Memory, st.HistoryMemory)
+		}
+		u.Close()
+	}
+}
+
+func TestBaseUniverse_Fork(t *testing.T) {
+	for _, e := range engineNames() {
+		t.Run(e, func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height, o.MaxSteps = 10, 10, 0
+			o.Advanced = map[string]interface{}{AdvHistory: 4}
+			u := engines[e](o, nil)
+			defer u.Close()
+			u.Settle([][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}) //glider
+			want := []Area{u.Area()}
+			for i := 1; i <= 6; i++ {
+				u.StepSync()
+				want = append(want, u.Area())
+			}
+			if first, last, ok := u.History(); !ok || first != 3 || last != 6 {
+				t.Fatalf("got history %v-%v, %v, want 3-6", first, last, ok)
+			}
+			//the past generations are viewed without changing the field
+			if a, ok := u.Generation(4); !ok || !reflect.DeepEqual(a.Entities, want[4].Entities) {
+				t.Errorf("got generation 4 %v, want %v", a.Entities, want[4].Entities)
+			}
+			if st := u.Status(); st.IterationNum != 6 || !reflect.DeepEqual(u.Area().Entities, want[6].Entities) {
+				t.Errorf("got iteration %v after viewing the past, want the field of 6", st.IterationNum)
+			}
+			if err := u.Fork(2); !errors.Is(err, ErrGenerationNotKept) {
+				t.Errorf("got error %v forking the dropped generation, want %v", err, ErrGenerationNotKept)
+			}
+			//forking drops the future and continues from the past
+			if err := u.Fork(4); err != nil {
+				t.Fatal(err)
+			}
+			if st := u.Status(); st.IterationNum != 4 || !reflect.DeepEqual(u.Area().Entities, want[4].Entities) {
+				t.Errorf("got iteration %v after forking, want the field of 4", st.IterationNum)
+			}
+			if first, last, ok := u.History(); !ok || first != 3 || last != 4 {
+				t.Errorf("got history %v-%v, %v after forking, want 3-4", first, last, ok)
+			}
+			u.StepSync()
+			if st := u.Status(); st.IterationNum != 5 || !reflect.DeepEqual(u.Area().Entities, want[5].Entities) {
+				t.Errorf("got iteration %v after the step, want the field of 5", st.IterationNum)
+			}
+			//the history is restarted when the field is changed by hand
+			u.SetCell(9, 9, true)
+			if _, _, ok := u.History(); ok {
+				t.Error("got the history after drawing, want none")
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package universe
+
+import "time"
+
+/*
+Hashlife Universe implementation
+The area is converted to the canonical quadtree, the memoized node results are reused between the steps,
+so the repetitive patterns are calculated much faster and StepPow2 jumps 2^k generations at once
+The quadtree evolves the unbounded plane: the cells leaving the area are dropped after the each step (or jump),
+so the jump differs from the steps one by one when the cells reach the area edges during the jump
+the wrapped boundaries and the rules with B0 can't be calculated this way and use the base algorithm
+Only the area (the viewport) is rendered from the tree
+*/
+type HashlifeUniverse struct {
+	*BaseUniverse
+	tree     *quadTree
+	jump     int //the power of 2 of the generations count calculated by the next step
+	maxNodes int //the node table size the jump is shortened at
+	tmpBuff  Area
+}
+
+func NewHashlifeUniverse(o *Options, stateCh chan Status) (Universe, error) {
+	bu, err := NewBaseUniverse(o, stateCh)
+	if err != nil {
+		return nil, err
+	}
+	hu := HashlifeUniverse{BaseUniverse: bu, maxNodes: DefMaxQuadNodes}
+	//redefine the nextIteration
+	hu.BaseUniverse.nextIteration = hu.nextIteration
+	hu.BaseUniverse.memoryUsage = hu.memoryUsage
+	hu.tmpBuff = createArea(hu.area.Width, hu.area.Height)
+	hu.options.Advanced["engine"] = "hashlife"
+	return &hu, nil
+}
+
+//the largest powers of 2 of the generations count StepPow2 advances at once
+const (
+	MaxJumpPow2     = 30
+	MaxBaseJumpPow2 = 10 //the jump done by the base steps, see StepPow2
+)
+
+//StepPow2 stops the simulation and advances it by 2^k generations at once, k is clamped to [0, MaxJumpPow2]
+//the cells leaving the area are dropped at the end of the jump only, so the patterns reaching the edges
+//during the jump may differ from the ones of 2^k Step calls
+//the jump is shortened to the largest one fitting the DefMaxQuadNodes nodes table
+//the wrapped boundaries, B0 and the multi-color rules do the base steps one by one, so k is clamped to MaxBaseJumpPow2 there
+//and the jump is stopped when the field dies out or doesn't change
+func (hu *HashlifeUniverse) StepPow2(k int) {
+	if k < 0 {
+		k = 0
+	}
+	if k > MaxJumpPow2 {
+		k = MaxJumpPow2
+	}
+	hu.controlCh <- func() {
+		hu.jump = k
+		hu.manualStep()
+		hu.jump = 0
+	}
+}
+
+func (hu *HashlifeUniverse) nextIteration() (hasLiveEnitities bool, changed bool) {
+	if hu.wrapX || hu.wrapY || hu.rule.Birth[0] || hu.rule.Colors > 0 {
+		//the plane is not wrapped by the tree, the empty space gives birth with B0, the nodes don't keep the colors
+		start := time.Now()
+		generations := 0
+		for generations < 1<<uint(min(hu.jump, MaxBaseJumpPow2)) {
+			hasLiveEnitities, changed = hu._nextIteration()
+			generations++
+			if !hasLiveEnitities || !changed {
+				//the extinct or stable field is not advanced further, the step finishes the simulation
+				break
+			}
+		}
+		hu.state.Lock()
+		hu.state.IterationTime = time.Since(start)
+		hu.state.IterationNum += generations - 1
+		hu.state.Unlock()
+		return
+	}
+
+	hu.area.Lock()
+	defer hu.area.Unlock()
+	start := time.Now()
+	if hu.tree == nil || hu.tree.rule != hu.rule || len(hu.tree.nodes) > hu.maxNodes {
+		hu.tree = newQuadTree(hu.rule)
+	}
+	//the centre of the root covers the area and the jump fits the root level
+	level := 2
+	for 1<<uint(level-1) < hu.area.Width || 1<<uint(level-1) < hu.area.Height || level-2 < hu.jump {
+		level++
+	}
+	offset := 1 << uint(level-2)
+	root := hu.tree.fromArea(hu.area.Area, level, -offset, -offset)
+	if hu.jump > 0 {
+		//the oscillators can return to the same state after the jump, so the change is checked on the first generation
+		_, changed = hu.render(hu.tree.advance(root, 0))
+	}
+	next := hu.advanceWithin(root)
+	generations := 1 << uint(hu.jump)
+	liveCells, c := hu.render(next)
+	changed = changed || c
+
+	for y := range hu.area.Entities {
+		copy(hu.area.Entities[y], hu.tmpBuff.Entities[y])
+	}
+
+	hu.state.Lock()
+	hu.state.LiveCells = liveCells
+	hu.state.IterationTime = time.Since(start)
+	hu.state.IterationNum += generations - 1
+	hu.state.Unlock()
+	hasLiveEnitities = liveCells > 0
+	return
+}
+
+//advanceWithin advances the root by the jump keeping the nodes table within maxNodes
+//the jump is shortened with the new table when the table is full, the single generation is never limited
+func (hu *HashlifeUniverse) advanceWithin(root *QuadNode) *QuadNode {
+	for {
+		hu.tree.maxNodes = 0
+		if hu.jump > 0 {
+			hu.tree.maxNodes = hu.maxNodes
+		}
+		next := hu.tree.advance(root, hu.jump)
+		hu.tree.maxNodes = 0
+		if next != nil {
+			return next
+		}
+		hu.tree = newQuadTree(hu.rule)
+		hu.jump--
+		root = hu.tree.fromArea(hu.area.Area, root.Level, -(1 << uint(root.Level-2)), -(1 << uint(root.Level-2)))
+	}
+}
+
+//render draws the part of the node covering the area to the tmpBuff
+//returns the live cells count and if the tmpBuff differs from the area
+func (hu *HashlifeUniverse) render(n *QuadNode) (liveCells int, changed bool) {
+	for y := range hu.tmpBuff.Entities {
+		for x := range hu.tmpBuff.Entities[y] {
+			hu.tmpBuff.Entities[y][x] = false
+		}
+	}
+	hu.tree.walkLive(n, 0, 0, func(x int, y int) {
+		if x < hu.tmpBuff.Width && y < hu.tmpBuff.Height {
+			hu.tmpBuff.Entities[y][x] = true
+			liveCells++
+		}
+	})
+	for y := range hu.tmpBuff.Entities {
+		for x := range hu.tmpBuff.Entities[y] {
+			if hu.tmpBuff.Entities[y][x] != hu.area.Entities[y][x] {
+				return liveCells, true
+			}
+		}
+	}
+	return
+}
+
+//memoryUsage estimates the bytes of the area, the buffer and the quadtree nodes table
+func (hu *HashlifeUniverse) memoryUsage() int {
+	bytes := areaBytes(hu.area.Area) + areaBytes(hu.tmpBuff)
+	if hu.tree != nil {
+		bytes += hu.tree.memoryUsage()
+	}
+	return bytes
+}
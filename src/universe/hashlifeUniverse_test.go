@@ -0,0 +1,139 @@
+package universe
+
+import (
+	"testing"
+)
+
+func TestHashlifeUniverse_SameAsBase(t *testing.T) {
+	//the R-pentomino grows up to the area edges
+	rPentomino := [][]int{{31, 30}, {32, 30}, {30, 31}, {31, 31}, {31, 32}}
+	o := newUniverseOptions()
+	o.Width, o.Height = 64, 48
+	baseCh, hashlifeCh := newStateCh(), newStateCh()
+	bu := newBaseUniverse(o, baseCh)
+	hu := mustUniverse(NewHashlifeUniverse)(o, hashlifeCh)
+	defer bu.Close()
+	defer hu.Close()
+	bu.Settle(rPentomino)
+	hu.Settle(rPentomino)
+	for i := 1; i <= 200; i++ {
+		bu.Step()
+		waitMode(baseCh, RunningStateManual)
+		hu.Step()
+		waitMode(hashlifeCh, RunningStateManual)
+		if d, _ := Diff(bu.Area(), hu.Area()); len(d) != 0 {
+			t.Fatalf("step %d: the areas differ in %d cells", i, len(d))
+		}
+		if bu.Status().LiveCells != hu.Status().LiveCells {
+			t.Fatalf("step %d: got %d live cells, want %d", i, hu.Status().LiveCells, bu.Status().LiveCells)
+		}
+	}
+}
+
+func TestHashlifeUniverse_StepPow2(t *testing.T) {
+	glider := [][]int{{11, 10}, {12, 11}, {10, 12}, {11, 12}, {12, 12}}
+	const k = 5
+	o := newUniverseOptions()
+	o.Width, o.Height = 60, 60
+	baseCh, hashlifeCh := newStateCh(), newStateCh()
+	bu := newBaseUniverse(o, baseCh)
+	hu := mustUniverse(NewHashlifeUniverse)(o, hashlifeCh).(*HashlifeUniverse)
+	defer bu.Close()
+	defer hu.Close()
+	bu.Settle(glider)
+	hu.Settle(glider)
+	for i := 0; i < 1<<k; i++ {
+		bu.Step()
+		waitMode(baseCh, RunningStateManual)
+	}
+	hu.StepPow2(k)
+	st := waitMode(hashlifeCh, RunningStateManual)
+	if st.IterationNum != 1<<k {
+		t.Errorf("got iteration %d, want %d", st.IterationNum, 1<<k)
+	}
+	if d, _ := Diff(bu.Area(), hu.Area()); len(d) != 0 {
+		t.Errorf("the areas differ in %d cells", len(d))
+	}
+}
+
+func TestHashlifeUniverse_StepPow2Clamped(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height, o.MaxSteps = 20, 20, 0
+	ch := newStateCh()
+	hu := mustUniverse(NewHashlifeUniverse)(o, ch).(*HashlifeUniverse)
+	defer hu.Close()
+	hu.Settle([][]int{{11, 10}, {12, 11}, {10, 12}, {11, 12}, {12, 12}})
+	hu.StepPow2(62)
+	st := waitMode(ch, RunningStateFinished)
+	if st.IterationNum != 1<<MaxJumpPow2 {
+		t.Errorf("got iteration %d, want %d", st.IterationNum, 1<<MaxJumpPow2)
+	}
+}
+
+func TestHashlifeUniverse_StepPow2Fallback(t *testing.T) {
+	glider := [][]int{{11, 10}, {12, 11}, {10, 12}, {11, 12}, {12, 12}}
+	block := [][]int{{1, 1}, {2, 1}, {1, 2}, {2, 2}}
+	tests := []struct {
+		name  string
+		cells [][]int
+		k     int
+		want  int
+		mode  RunningState
+	}{
+		{"clamped", glider, MaxJumpPow2, 1 << MaxBaseJumpPow2, RunningStateManual},
+		{"short", glider, 3, 8, RunningStateManual},
+		//the stable field finishes the jump at once
+		{"stable", block, 5, 1, RunningStateFinished},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height, o.MaxSteps = 20, 20, 0
+			o.Advanced = map[string]interface{}{AdvBoundary: string(BoundaryTorus)}
+			hashlifeCh := newStateCh()
+			bu := newBaseUniverse(o, nil)
+			hu := mustUniverse(NewHashlifeUniverse)(o, hashlifeCh).(*HashlifeUniverse)
+			defer bu.Close()
+			defer hu.Close()
+			bu.Settle(tt.cells)
+			hu.Settle(tt.cells)
+			hu.StepPow2(tt.k)
+			st := waitMode(hashlifeCh, tt.mode)
+			if st.IterationNum != tt.want {
+				t.Fatalf("got iteration %d, want %d", st.IterationNum, tt.want)
+			}
+			for i := 0; i < tt.want; i++ {
+				bu.StepSync()
+			}
+			if d, _ := Diff(bu.Area(), hu.Area()); len(d) != 0 {
+				t.Errorf("the areas differ in %d cells", len(d))
+			}
+		})
+	}
+}
+
+func TestHashlifeUniverse_StepPow2MaxNodes(t *testing.T) {
+	glider := [][]int{{11, 10}, {12, 11}, {10, 12}, {11, 12}, {12, 12}}
+	o := newUniverseOptions()
+	o.Width, o.Height, o.MaxSteps = 60, 60, 0
+	hashlifeCh := newStateCh()
+	bu := newBaseUniverse(o, nil)
+	hu := mustUniverse(NewHashlifeUniverse)(o, hashlifeCh).(*HashlifeUniverse)
+	defer bu.Close()
+	defer hu.Close()
+	hu.maxNodes = 100
+	bu.Settle(glider)
+	hu.Settle(glider)
+	hu.StepPow2(5)
+	st := waitMode(hashlifeCh, RunningStateManual)
+	//the jump is shortened to fit the nodes table
+	if st.IterationNum >= 1<<5 || st.IterationNum&(st.IterationNum-1) != 0 {
+		t.Fatalf("got iteration %d, want the power of 2 less than %d", st.IterationNum, 1<<5)
+	}
+	for i := 0; i < st.IterationNum; i++ {
+		bu.StepSync()
+	}
+	if d, _ := Diff(bu.Area(), hu.Area()); len(d) != 0 {
+		t.Errorf("the areas differ in %d cells", len(d))
+	}
+}
@@ -0,0 +1,55 @@
+package universe
+
+import (
+	"time"
+)
+
+//StepHistogramBounds are the upper bounds of the step duration histogram buckets, the last bucket counts the longer steps
+var StepHistogramBounds = [...]time.Duration{
+	time.Microsecond * 10,
+	time.Microsecond * 100,
+	time.Millisecond,
+	time.Millisecond * 10,
+	time.Millisecond * 100,
+	time.Second,
+}
+
+//StepHistogramBuckets is the count of the step duration histogram buckets
+const StepHistogramBuckets = len(StepHistogramBounds) + 1
+
+//DefHistogramSteps is the count of the last steps counted by the step duration histogram
+const DefHistogramSteps = 256
+
+//StepHistogram is the count of the last steps per duration bucket
+type StepHistogram [StepHistogramBuckets]int
+
+//stepHistory is the ring of the buckets of the last DefHistogramSteps steps, the histogram is updated incrementally
+type stepHistory struct {
+	buckets [DefHistogramSteps]uint8
+	next    int
+	full    bool
+}
+
+//stepBucket returns the histogram bucket of the step duration
+func stepBucket(d time.Duration) int {
+	for i, bound := range StepHistogramBounds {
+		if d <= bound {
+			return i
+		}
+	}
+	return len(StepHistogramBounds)
+}
+
+//add counts the step duration in the histogram dropping the oldest step if the ring is full
+func (sh *stepHistory) add(h *StepHistogram, d time.Duration) {
+	if sh.full {
+		h[sh.buckets[sh.next]]--
+	}
+	b := stepBucket(d)
+	sh.buckets[sh.next] = uint8(b)
+	h[b]++
+	sh.next++
+	if sh.next == DefHistogramSteps {
+		sh.next, sh.full = 0, true
+	}
+}
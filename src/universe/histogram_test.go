@@ -0,0 +1,51 @@
+package universe
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStepHistory(t *testing.T) {
+	var h StepHistogram
+	var sh stepHistory
+	sh.add(&h, time.Microsecond*10)
+	sh.add(&h, time.Microsecond*11)
+	sh.add(&h, time.Minute)
+	if want := (StepHistogram{1, 1, 0, 0, 0, 0, 1}); h != want {
+		t.Fatalf("got %v, want %v", h, want)
+	}
+	//the oldest steps are dropped
+	for i := 0; i < DefHistogramSteps-1; i++ {
+		sh.add(&h, time.Millisecond*5)
+	}
+	if want := (StepHistogram{0, 0, 0, DefHistogramSteps - 1, 0, 0, 1}); h != want {
+		t.Errorf("got %v, want %v", h, want)
+	}
+}
+
+func TestBaseUniverse_StepHistogram(t *testing.T) {
+	o := newUniverseOptions()
+	o.MaxSteps = 0
+	u := newBaseUniverse(o, nil)
+	defer u.Close()
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	for i := 0; i < 3; i++ {
+		u.StepSync()
+	}
+	sum := 0
+	for _, n := range u.Status().StepHistogram {
+		sum += n
+	}
+	if sum != 3 {
+		t.Errorf("got %v steps in the histogram, want 3", sum)
+	}
+	u.Clear()
+	u.StepSync()
+	sum = 0
+	for _, n := range u.Status().StepHistogram {
+		sum += n
+	}
+	if sum != 1 {
+		t.Errorf("got %v steps in the histogram after clearing, want 1", sum)
+	}
+}
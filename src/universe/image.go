@@ -0,0 +1,177 @@
+package universe
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" //the decoders are registered for image.Decode
+	"image/png"
+	"io"
+)
+
+//DefImageThreshold is the luminance below which the pixel is a live cell
+const DefImageThreshold = 128
+
+//imagePalette is the palette of the saved images: the dead cells, the live cells and the colors of the multi-color rules
+//the live colors are darker than DefImageThreshold, so the saved image is loaded back as the same field
+var imagePalette = color.Palette{
+	color.White,
+	color.Black,
+	color.RGBA{R: 0, G: 128, B: 0, A: 255},
+	color.RGBA{R: 192, G: 0, B: 0, A: 255},
+	color.RGBA{R: 0, G: 0, B: 192, A: 255},
+	color.RGBA{R: 128, G: 128, B: 0, A: 255},
+}
+
+var (
+	ErrInvalidImage = errors.New("invalid image")
+)
+
+//the corners of the image label
+const (
+	LabelTopLeft     = "top-left"
+	LabelTopRight    = "top-right"
+	LabelBottomLeft  = "bottom-left"
+	LabelBottomRight = "bottom-right"
+)
+
+//LabelCorners is the list of the image label corners
+var LabelCorners = []string{LabelTopLeft, LabelTopRight, LabelBottomRight, LabelBottomLeft}
+
+//ImageLabel is the generation and optionally the population drawn in the corner of the saved image
+type ImageLabel struct {
+	Corner         string //one of LabelCorners, the label is not drawn if empty
+	Generation     int
+	Population     int
+	ShowPopulation bool
+}
+
+//labelFont is the 3x5 bitmap font of the label text
+var labelFont = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {"##.", ".#.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'G': {"###", "#..", "#.#", "#.#", "###"},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'P': {"###", "#.#", "###", "#..", "#.."},
+	'O': {"###", "#.#", "#.#", "#.#", "###"},
+	'-': {"...", "...", "###", "...", "..."},
+}
+
+//the label font cell size in the font dots, the glyph and the spacing
+const (
+	labelGlyphWidth  = 4
+	labelGlyphHeight = 6
+)
+
+//LoadImage decodes the PNG or JPEG image, the pixels darker than the threshold luminance become the live cells
+//the transparent pixels are dead, the area has the image dimensions: the image is not scaled,
+//the part beyond the field is cropped when the area is placed to the field (see SettleLayout)
+func LoadImage(r io.Reader, threshold uint8) (Area, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return Area{}, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	b := img.Bounds()
+	a := createArea(b.Dx(), b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			c := img.At(b.Min.X+x, b.Min.Y+y)
+			if _, _, _, alpha := c.RGBA(); alpha == 0 {
+				continue
+			}
+			a.Entities[y][x] = Cell(color.GrayModel.Convert(c).(color.Gray).Y < threshold)
+		}
+	}
+	return a, nil
+}
+
+//SaveImage encodes the area as the PNG image with the cells of scale x scale pixels
+//the live cells are black on white, the cells of the multi-color rules are colored
+func SaveImage(w io.Writer, a Area, scale int) error {
+	return SaveLabeledImage(w, a, scale, ImageLabel{})
+}
+
+//SaveLabeledImage encodes the area as SaveImage does with the label drawn over the cells in the label corner
+//the label dots are scale/2 pixels, the part of the label beyond the image is cropped
+func SaveLabeledImage(w io.Writer, a Area, scale int, l ImageLabel) error {
+	if scale < 1 {
+		scale = 1
+	}
+	img := image.NewPaletted(image.Rect(0, 0, a.Width*scale, a.Height*scale), imagePalette)
+	for y := range a.Entities {
+		for x, e := range a.Entities[y] {
+			if !e {
+				continue
+			}
+			//the palette index of the live cell
+			c := uint8(1)
+			if a.Colors != nil && a.Colors[y][x] > 0 && int(a.Colors[y][x]) <= MaxColors {
+				c += a.Colors[y][x]
+			}
+			for py := y * scale; py < (y+1)*scale; py++ {
+				for px := x * scale; px < (x+1)*scale; px++ {
+					img.SetColorIndex(px, py, c)
+				}
+			}
+		}
+	}
+	if l.Corner != "" {
+		drawLabel(img, l, max(1, scale/2))
+	}
+	return png.Encode(w, img)
+}
+
+//drawLabel draws the label text in black on the white box with the margin of one dot of dot x dot pixels
+func drawLabel(img *image.Paletted, l ImageLabel, dot int) {
+	lines := []string{fmt.Sprintf("GEN %d", l.Generation)}
+	if l.ShowPopulation {
+		lines = append(lines, fmt.Sprintf("POP %d", l.Population))
+	}
+	chars := 0
+	for _, line := range lines {
+		chars = max(chars, len(line))
+	}
+	//the box size in dots, the spacing after the last glyph and line is the margin
+	w, h := 1+chars*labelGlyphWidth, 1+len(lines)*labelGlyphHeight
+	b := img.Bounds()
+	x0, y0 := 0, 0
+	if l.Corner == LabelTopRight || l.Corner == LabelBottomRight {
+		x0 = b.Dx() - w*dot
+	}
+	if l.Corner == LabelBottomLeft || l.Corner == LabelBottomRight {
+		y0 = b.Dy() - h*dot
+	}
+	fill := func(dx int, dy int, c uint8) {
+		for py := y0 + dy*dot; py < y0+(dy+1)*dot; py++ {
+			for px := x0 + dx*dot; px < x0+(dx+1)*dot; px++ {
+				img.SetColorIndex(px, py, c)
+			}
+		}
+	}
+	for dy := 0; dy < h; dy++ {
+		for dx := 0; dx < w; dx++ {
+			fill(dx, dy, 0)
+		}
+	}
+	for i, line := range lines {
+		for j, r := range line {
+			for gy, row := range labelFont[r] {
+				for gx, c := range row {
+					if c == '#' {
+						fill(1+j*labelGlyphWidth+gx, 1+i*labelGlyphHeight+gy, 1)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package universe
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestLoadImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	img.Set(0, 0, color.Black)
+	img.Set(1, 0, color.Gray{Y: 100})
+	img.Set(2, 0, color.Gray{Y: 200})
+	img.Set(3, 1, color.NRGBA{A: 0}) //transparent black is dead
+	img.Set(1, 1, color.NRGBA{R: 200, A: 255})
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	a, err := LoadImage(bytes.NewReader(b.Bytes()), DefImageThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the red of 200 has the luminance of 60
+	if d, err := Diff(newTestArea(4, 2, [][]int{{0, 0}, {1, 0}, {1, 1}}), a); err != nil || len(d) != 0 {
+		t.Errorf("the area differs in %v (%v)", d, err)
+	}
+	if _, err := LoadImage(strings.NewReader("not an image"), DefImageThreshold); !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("got error %v, want %v", err, ErrInvalidImage)
+	}
+}
+
+func TestSaveImage(t *testing.T) {
+	a := newTestArea(4, 3, [][]int{{0, 0}, {1, 1}, {3, 2}})
+	var b bytes.Buffer
+	if err := SaveImage(&b, a, 3); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := img.Bounds().Size(); s.X != 12 || s.Y != 9 {
+		t.Errorf("got image %v, want 12x9", s)
+	}
+	//the cell is scaled, the saved image is loaded back as the same cells
+	loaded, err := LoadImage(bytes.NewReader(b.Bytes()), DefImageThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := newTestArea(12, 9, nil)
+	for _, p := range [][]int{{0, 0}, {1, 1}, {3, 2}} {
+		for y := p[1] * 3; y < p[1]*3+3; y++ {
+			for x := p[0] * 3; x < p[0]*3+3; x++ {
+				want.Entities[y][x] = true
+			}
+		}
+	}
+	if d, err := Diff(want, loaded); err != nil || len(d) != 0 {
+		t.Errorf("the loaded area differs in %v (%v)", d, err)
+	}
+	//the colors of the multi-color rules are kept dark
+	a.Colors = createColors(a.Width, a.Height)
+	for i, p := range [][]int{{0, 0}, {1, 1}, {3, 2}} {
+		a.Colors[p[1]][p[0]] = uint8(i + 2)
+	}
+	b.Reset()
+	if err := SaveImage(&b, a, 1); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err = LoadImage(bytes.NewReader(b.Bytes()), DefImageThreshold); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := Diff(newTestArea(4, 3, [][]int{{0, 0}, {1, 1}, {3, 2}}), loaded); err != nil || len(d) != 0 {
+		t.Errorf("the loaded colored area differs in %v (%v)", d, err)
+	}
+}
+
+func TestSaveLabeledImage(t *testing.T) {
+	cells := make([][]int, 0)
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 20; x++ {
+			cells = append(cells, []int{x, y})
+		}
+	}
+	a := newTestArea(20, 10, cells)
+	//the label dots are 2x2 pixels with the scale 4, "GEN 7" is 21x7 dots with the margin
+	tests := []struct {
+		name  string
+		label ImageLabel
+		black []image.Point
+		white []image.Point
+	}{
+		{"none", ImageLabel{Generation: 7}, []image.Point{{0, 0}, {79, 39}}, nil},
+		{"top left", ImageLabel{Corner: LabelTopLeft, Generation: 7},
+			[]image.Point{{2, 2}, {7, 2}, {42, 0}, {0, 14}},
+			[]image.Point{{0, 0}, {41, 13}, {4, 4}}},
+		{"bottom right", ImageLabel{Corner: LabelBottomRight, Generation: 7},
+			[]image.Point{{40, 28}, {0, 0}, {37, 39}},
+			[]image.Point{{38, 26}, {79, 39}, {42, 30}}},
+		{"population", ImageLabel{Corner: LabelTopLeft, Generation: 7, Population: 200, ShowPopulation: true},
+			[]image.Point{{2, 14}, {2, 22}, {0, 26}},
+			[]image.Point{{0, 25}, {4, 22}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := SaveLabeledImage(&b, a, 4, tt.label); err != nil {
+				t.Fatal(err)
+			}
+			img, err := png.Decode(bytes.NewReader(b.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range tt.black {
+				if c := color.GrayModel.Convert(img.At(p.X, p.Y)).(color.Gray); c.Y != 0 {
+					t.Errorf("got %v at %v, want black", c, p)
+				}
+			}
+			for _, p := range tt.white {
+				if c := color.GrayModel.Convert(img.At(p.X, p.Y)).(color.Gray); c.Y != 255 {
+					t.Errorf("got %v at %v, want white", c, p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package universe
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//Placement is the template stamped to the field with the offset
+type Placement struct {
+	Name   string
+	X      int
+	Y      int
+	Anchor Anchor //the point of the template placed at X, Y, AnchorTopLeft if empty
+}
+
+//Anchor is the point of the template placed at the placement position
+type Anchor string
+
+//anchors
+const (
+	AnchorTopLeft Anchor = "top-left" //the corner of the template bounding box, it is the bottom left corner with the bottom-left origin
+	AnchorCenter  Anchor = "center"   //the middle cell of the template bounding box, the farther from the origin of the two middle ones
+)
+
+//Anchors is the list of the anchors, the first is the default
+var Anchors = []Anchor{AnchorTopLeft, AnchorCenter}
+
+var (
+	ErrInvalidLayout = errors.New("invalid layout")
+)
+
+//LoadLayout reads the layout: the list of "name x y [anchor]" lines, the empty lines and the lines started with # are skipped
+//the anchor is one of Anchors, AnchorTopLeft if omitted
+//returns ErrInvalidLayout with the line number for the malformed lines
+func LoadLayout(r io.Reader) ([]Placement, error) {
+	placements := make([]Placement, 0)
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 3 && len(fields) != 4 {
+			return nil, fmt.Errorf("%w: line %d: want \"name x y [anchor]\"", ErrInvalidLayout, line)
+		}
+		x, errX := strconv.Atoi(fields[1])
+		y, errY := strconv.Atoi(fields[2])
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("%w: line %d: the coordinates should be integers", ErrInvalidLayout, line)
+		}
+		p := Placement{Name: fields[0], X: x, Y: y}
+		if len(fields) == 4 {
+			p.Anchor = Anchor(fields[3])
+			if !validAnchor(p.Anchor) {
+				return nil, fmt.Errorf("%w: line %d: unknown anchor %q", ErrInvalidLayout, line, fields[3])
+			}
+		}
+		placements = append(placements, p)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return placements, nil
+}
+
+//SettleLayout clears the universe and stamps the templates by the placements in order
+//if merge is set the field is not cleared, the live cells of the templates are added to the current ones
+//the placements are measured from the bottom left corner if the AdvOrigin option is bottom-left, the templates are not mirrored
+//returns the warnings about the unknown templates and the placements not fitting the field
+//the cells crossing the wrapped edges appear on the opposite side, the cells outside the field are dropped otherwise
+func (u *BaseUniverse) SettleLayout(placements []Placement, merge bool) (warnings []string) {
+	done := make(chan []string)
+	if !merge {
+		u.controlCh <- u.clear
+	}
+	u.controlCh <- func() {
+		warnings := make([]string, 0)
+		u.area.Lock()
+		for _, p := range placements {
+			tmpl, ok := u.templates[p.Name]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("unknown template %q", p.Name))
+				continue
+			}
+			if outside := u.place(tmpl, p.X, p.Y, p.Anchor); outside {
+				warnings = append(warnings, fmt.Sprintf("template %q at %d,%d is out of the field bounds", p.Name, p.X, p.Y))
+			}
+		}
+		u.publishArea(Area{})
+		u.area.Unlock()
+		u.setLiveCells(u.liveCells())
+		u.reactivate()
+		u.refreshView()
+		done <- warnings
+	}
+	return <-done
+}
+
+//InsertPattern adds the live cells of the pattern to the field, the anchor of the pattern is placed at x, y as the layout templates
+//the empty anchor is AnchorTopLeft, the pattern needn't be added by AddTemplate, returns true if any cell is dropped outside the field
+func (u *BaseUniverse) InsertPattern(tmpl Template, x int, y int, anchor Anchor) (outside bool) {
+	done := make(chan bool)
+	u.controlCh <- func() {
+		u.area.Lock()
+		outside := u.place(tmpl, x, y, anchor)
+		u.publishArea(Area{})
+		u.area.Unlock()
+		u.setLiveCells(u.liveCells())
+		u.reactivate()
+		u.refreshView()
+		done <- outside
+	}
+	return <-done
+}
+
+//place stamps the template with the anchor at x, y measured from the origin set by the AdvOrigin option
+//should be called by the main loop under the area lock
+func (u *BaseUniverse) place(tmpl Template, x int, y int, anchor Anchor) (outside bool) {
+	if anchor == AnchorCenter {
+		x -= templateWidth(tmpl) / 2
+		y -= templateHeight(tmpl) / 2
+	}
+	if u.advancedString(AdvOrigin, OriginTopLeft) == OriginBottomLeft {
+		//the placement is the bottom left corner of the template measured from the bottom of the field
+		y = u.area.Height - y - templateHeight(tmpl)
+	}
+	return u.stamp(tmpl.Coordinates, x, y)
+}
+
+//stamp settles the live cells at the coordinates shifted by dx, dy
+//the coordinates are wrapped by the boundary, returns true if any cell is dropped outside the area
+//should be called under the area lock
+func (u *BaseUniverse) stamp(vc [][]int, dx int, dy int) (outside bool) {
+	for _, v := range vc {
+		x, y := v[0]+dx, v[1]+dy
+		if u.wrapX {
+			x = (x%u.area.Width + u.area.Width) % u.area.Width
+		}
+		if u.wrapY {
+			y = (y%u.area.Height + u.area.Height) % u.area.Height
+		}
+		if x < 0 || y < 0 || x >= u.area.Width || y >= u.area.Height {
+			outside = true
+			continue
+		}
+		u.setCell(x, y, true, coordinateColor(v))
+	}
+	return
+}
+
+//templateWidth returns the width of the template bounding box
+func templateWidth(tmpl Template) (w int) {
+	for _, v := range tmpl.Coordinates {
+		if v[0]+1 > w {
+			w = v[0] + 1
+		}
+	}
+	return
+}
+
+//validAnchor returns true if the anchor is one of Anchors or empty
+func validAnchor(a Anchor) bool {
+	if a == "" {
+		return true
+	}
+	for _, known := range Anchors {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+//templateHeight returns the height of the template bounding box
+func templateHeight(tmpl Template) (h int) {
+	for _, v := range tmpl.Coordinates {
+		if v[1]+1 > h {
+			h = v[1] + 1
+		}
+	}
+	return
+}
@@ -0,0 +1,138 @@
+package universe
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  string
+		want    []Placement
+		wantErr error
+	}{
+		{"empty", "", []Placement{}, nil},
+		{"placements", "# the machine\nglider 1 2\n\n  block 10 -3  \n", []Placement{{"glider", 1, 2, ""}, {"block", 10, -3, ""}}, nil},
+		{"anchor", "glider 1 2 center\nblock 3 4 top-left\n", []Placement{{"glider", 1, 2, AnchorCenter}, {"block", 3, 4, AnchorTopLeft}}, nil},
+		{"unknown anchor", "glider 1 2 middle\n", nil, ErrInvalidLayout},
+		{"missing coordinate", "glider 1\n", nil, ErrInvalidLayout},
+		{"bad coordinate", "glider 1 y\n", nil, ErrInvalidLayout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadLayout(strings.NewReader(tt.layout))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseUniverse_SettleLayout(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 10, 10
+	u := newBaseUniverse(o, newStateCh())
+	defer u.Close()
+	u.AddTemplate(Template{"block", "", [][]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}}})
+	u.Settle([][]int{{5, 5}})
+	warnings := u.SettleLayout([]Placement{{"block", 0, 0, ""}, {"glider", 3, 3, ""}, {"block", 9, 8, ""}}, false)
+	if len(warnings) != 2 {
+		t.Errorf("got warnings %v, want 2", warnings)
+	}
+	want := newTestArea(10, 10, [][]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {9, 8}, {9, 9}})
+	if d, _ := Diff(want, u.Area()); len(d) != 0 {
+		t.Errorf("the area differs in %v", d)
+	}
+	if st := u.Status(); st.LiveCells != 6 {
+		t.Errorf("got %d live cells, want 6", st.LiveCells)
+	}
+}
+
+func TestBaseUniverse_SettleLayoutWrapped(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 10, 10
+	o.Advanced = map[string]interface{}{AdvBoundary: string(BoundaryTorus)}
+	u := newBaseUniverse(o, newStateCh())
+	defer u.Close()
+	u.AddTemplate(Template{"glider", "", [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}})
+	//the glider straddles the bottom right corner
+	if warnings := u.SettleLayout([]Placement{{"glider", 8, 8, ""}}, false); len(warnings) != 0 {
+		t.Errorf("got warnings %v, want none", warnings)
+	}
+	want := newTestArea(10, 10, [][]int{{9, 8}, {0, 9}, {8, 0}, {9, 0}, {0, 0}})
+	if d, _ := Diff(want, u.Area()); len(d) != 0 {
+		t.Errorf("the area differs in %v", d)
+	}
+}
+
+func TestBaseUniverse_SettleLayoutBottomLeft(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 10, 10
+	o.Advanced = map[string]interface{}{AdvOrigin: OriginBottomLeft}
+	u := newBaseUniverse(o, newStateCh())
+	defer u.Close()
+	//the L shape is kept as is, its bottom left corner is placed at 1, 0
+	u.AddTemplate(Template{"L", "", [][]int{{0, 0}, {0, 1}, {1, 1}}})
+	u.SettleLayout([]Placement{{"L", 1, 0, ""}}, false)
+	want := newTestArea(10, 10, [][]int{{1, 8}, {1, 9}, {2, 9}})
+	if d, _ := Diff(want, u.Area()); len(d) != 0 {
+		t.Errorf("the area differs in %v", d)
+	}
+}
+
+func TestBaseUniverse_SettleLayoutMerge(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 10, 10
+	u := newBaseUniverse(o, newStateCh())
+	defer u.Close()
+	u.AddTemplate(Template{"block", "", [][]int{{0, 0}, {1, 0}, {0, 1}, {1, 1}}})
+	u.Settle([][]int{{5, 5}, {1, 1}})
+	//the overlapping cell stays alive, the cells outside the field are dropped
+	if warnings := u.SettleLayout([]Placement{{"block", 1, 1, ""}, {"block", 9, 9, ""}}, true); len(warnings) != 1 {
+		t.Errorf("got warnings %v, want 1", warnings)
+	}
+	want := newTestArea(10, 10, [][]int{{5, 5}, {1, 1}, {2, 1}, {1, 2}, {2, 2}, {9, 9}})
+	if d, _ := Diff(want, u.Area()); len(d) != 0 {
+		t.Errorf("the area differs in %v", d)
+	}
+	if st := u.Status(); st.LiveCells != 6 {
+		t.Errorf("got %d live cells, want 6", st.LiveCells)
+	}
+}
+
+func TestBaseUniverse_InsertPatternAnchor(t *testing.T) {
+	//the center of the 3x2 pattern is in its row farther from the origin
+	tmpl := Template{"T", "", [][]int{{0, 0}, {1, 0}, {2, 0}, {1, 1}}}
+	tests := []struct {
+		name   string
+		origin string
+		anchor Anchor
+		want   [][]int
+	}{
+		{"default", OriginTopLeft, "", [][]int{{4, 4}, {5, 4}, {6, 4}, {5, 5}}},
+		{"top-left", OriginTopLeft, AnchorTopLeft, [][]int{{4, 4}, {5, 4}, {6, 4}, {5, 5}}},
+		{"center", OriginTopLeft, AnchorCenter, [][]int{{3, 3}, {4, 3}, {5, 3}, {4, 4}}},
+		{"center bottom-left", OriginBottomLeft, AnchorCenter, [][]int{{3, 5}, {4, 5}, {5, 5}, {4, 6}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newUniverseOptions()
+			o.Width, o.Height = 10, 10
+			o.Advanced = map[string]interface{}{AdvOrigin: tt.origin}
+			u := newBaseUniverse(o, nil)
+			defer u.Close()
+			if outside := u.InsertPattern(tmpl, 4, 4, tt.anchor); outside {
+				t.Error("the pattern is out of the field")
+			}
+			if d, _ := Diff(newTestArea(10, 10, tt.want), u.Area()); len(d) != 0 {
+				t.Errorf("the area differs in %v", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package universe
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//life106Header is the first line of the Life 1.06 file
+const life106Header = "#Life 1.06"
+
+//maxPatternCells is the largest area of the pattern files, the larger bounding boxes are rejected rather than allocated
+const maxPatternCells = 1 << 26
+
+var (
+	ErrInvalidLife106 = errors.New("invalid Life 1.06")
+)
+
+//LoadLife106 reads the Life 1.06 file - the header line followed by the "x y" lines of the live cells
+//the area is the bounding box of the cells, the negative coordinates are shifted to the origin
+//returns ErrInvalidLife106 with the line number for the malformed files and for the bounding box larger than maxPatternCells
+func LoadLife106(r io.Reader) (Area, error) {
+	s := bufio.NewScanner(r)
+	var points []Point
+	header := false
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		switch {
+		case !header:
+			if !strings.EqualFold(text, life106Header) {
+				return Area{}, fmt.Errorf("%w: line %d: the %q header is missing", ErrInvalidLife106, line, life106Header)
+			}
+			header = true
+		case text == "" || strings.HasPrefix(text, "#"):
+		default:
+			var p Point
+			var rest string
+			if n, _ := fmt.Sscan(text, &p.X, &p.Y, &rest); n != 2 {
+				return Area{}, fmt.Errorf("%w: line %d: malformed coordinates %q", ErrInvalidLife106, line, text)
+			}
+			points = append(points, p)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return Area{}, err
+	}
+	if !header {
+		return Area{}, fmt.Errorf("%w: the file is empty", ErrInvalidLife106)
+	}
+	if len(points) == 0 {
+		return createArea(0, 0), nil
+	}
+	x1, y1, x2, y2 := points[0].X, points[0].Y, points[0].X, points[0].Y
+	for _, p := range points {
+		x1, y1 = min(x1, p.X), min(y1, p.Y)
+		x2, y2 = max(x2, p.X), max(y2, p.Y)
+	}
+	//the far coordinates can overflow the size
+	w, h := x2-x1+1, y2-y1+1
+	if w <= 0 || h <= 0 || w > maxPatternCells || h > maxPatternCells || w*h > maxPatternCells {
+		return Area{}, fmt.Errorf("%w: the bounding box %d..%d x %d..%d is too large", ErrInvalidLife106, x1, x2, y1, y2)
+	}
+	a := createArea(w, h)
+	for _, p := range points {
+		a.Entities[p.Y-y1][p.X-x1] = Cell(true)
+	}
+	return a, nil
+}
+
+//SaveLife106 writes the live cells of the area as the Life 1.06 file
+//the coordinates are relative to the top left corner of the live cells bounding box
+func SaveLife106(w io.Writer, a Area) error {
+	return saveLife106(w, a, false)
+}
+
+//SaveLife106Absolute writes the live cells of the area as the Life 1.06 file with the area coordinates
+func SaveLife106Absolute(w io.Writer, a Area) error {
+	return saveLife106(w, a, true)
+}
+
+//saveLife106 writes the header and the "x y" line per live cell row by row
+func saveLife106(w io.Writer, a Area, absolute bool) error {
+	origin := Point{}
+	if lo, _, ok := LiveBounds(a); ok && !absolute {
+		origin = lo
+	}
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, life106Header)
+	for y := range a.Entities {
+		for x, c := range a.Entities[y] {
+			if c {
+				_, _ = fmt.Fprintf(bw, "%d %d\n", x-origin.X, y-origin.Y)
+			}
+		}
+	}
+	return bw.Flush()
+}
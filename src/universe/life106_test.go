@@ -0,0 +1,78 @@
+package universe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadLife106(t *testing.T) {
+	//the glider with the negative coordinates
+	glider := "#Life 1.06\n#D Glider\n0 -1\n1 0\n-1 1\n0 1\n1 1\n"
+	a, err := LoadLife106(strings.NewReader(glider))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err := Diff(newTestArea(3, 3, [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}), a); err != nil || len(d) != 0 {
+		t.Errorf("the area differs in %v (%v)", d, err)
+	}
+
+	tests := []struct {
+		name    string
+		lif     string
+		wantW   int
+		wantH   int
+		wantErr error
+	}{
+		{"empty pattern", "#Life 1.06\n", 0, 0, nil},
+		{"single cell", "#Life 1.06\n-5 7\n", 1, 1, nil},
+		{"duplicates", "#Life 1.06\n1 1\n1 1\n3 1\n", 3, 1, nil},
+		{"no header", "0 0\n1 1\n", 0, 0, ErrInvalidLife106},
+		{"empty file", "", 0, 0, ErrInvalidLife106},
+		{"malformed", "#Life 1.06\n0 0\n1 x\n", 0, 0, ErrInvalidLife106},
+		{"extra field", "#Life 1.06\n0 0 1\n", 0, 0, ErrInvalidLife106},
+		{"too large", "#Life 1.06\n0 0\n100000 100000\n", 0, 0, ErrInvalidLife106},
+		{"overflowed size", "#Life 1.06\n-9223372036854775808 0\n9223372036854775807 0\n", 0, 0, ErrInvalidLife106},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := LoadLife106(strings.NewReader(tt.lif))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if a.Width != tt.wantW || a.Height != tt.wantH {
+				t.Errorf("got %vx%v area, want %vx%v", a.Width, a.Height, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestSaveLife106(t *testing.T) {
+	a := newTestArea(10, 10, [][]int{{5, 3}, {6, 4}, {4, 5}, {5, 5}, {6, 5}})
+	var b strings.Builder
+	if err := SaveLife106(&b, a); err != nil {
+		t.Fatal(err)
+	}
+	if want := "#Life 1.06\n1 0\n2 1\n0 2\n1 2\n2 2\n"; b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+	b.Reset()
+	if err := SaveLife106Absolute(&b, a); err != nil {
+		t.Fatal(err)
+	}
+	if want := "#Life 1.06\n5 3\n6 4\n4 5\n5 5\n6 5\n"; b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+	//the pattern survives the round trip
+	loaded, err := LoadLife106(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Reset()
+	if err := SaveLife106Absolute(&b, loaded); err != nil {
+		t.Fatal(err)
+	}
+	if want := "#Life 1.06\n1 0\n2 1\n0 2\n1 2\n2 2\n"; b.String() != want {
+		t.Errorf("got %q after the round trip, want %q", b.String(), want)
+	}
+}
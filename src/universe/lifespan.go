@@ -0,0 +1,54 @@
+package universe
+
+//DefLifespanSteps is the steps limit of the lifespan analysis when the limit is not set
+const DefLifespanSteps = 100000
+
+//Lifespan is the outcome of the pattern run until it stabilizes
+type Lifespan struct {
+	Generations     int //the generation the final state is reached at, the steps limit if it is not reached
+	Period          int //the period of the final state: 1 - still life, 0 - extinct or not stabilized
+	FinalPopulation int //the population at Generations
+	PeakPopulation  int
+	PeakGeneration  int
+	FinishReason    FinishReason //FinishReasonStable, FinishReasonCycle, FinishReasonExtinct or FinishReasonMaxSteps
+}
+
+//AnalyzeLifespan runs the area up to maxSteps generations (DefLifespanSteps if 0) until it dies out or repeats
+//every state is remembered, so the generation the cycle starts at is exact unless the area hashes collide
+func AnalyzeLifespan(a Area, r Rule, b Boundary, maxSteps int) (l Lifespan) {
+	if maxSteps <= 0 {
+		maxSteps = DefLifespanSteps
+	}
+	p := population(a)
+	pops := []int{p}
+	seen := map[uint64]int{areaHash(a): 0}
+	l.PeakPopulation = p
+	for gen := 1; ; gen++ {
+		if p == 0 {
+			l.Generations, l.FinishReason = gen-1, FinishReasonExtinct
+			break
+		}
+		if gen > maxSteps {
+			l.Generations, l.FinishReason = maxSteps, FinishReasonMaxSteps
+			break
+		}
+		a = nextGeneration(a, r, b)
+		p = population(a)
+		pops = append(pops, p)
+		if p > l.PeakPopulation {
+			l.PeakPopulation, l.PeakGeneration = p, gen
+		}
+		h := areaHash(a)
+		if start, ok := seen[h]; ok {
+			l.Generations, l.Period = start, gen-start
+			l.FinishReason = FinishReasonCycle
+			if l.Period == 1 {
+				l.FinishReason = FinishReasonStable
+			}
+			break
+		}
+		seen[h] = gen
+	}
+	l.FinalPopulation = pops[l.Generations]
+	return
+}
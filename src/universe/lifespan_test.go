@@ -0,0 +1,43 @@
+package universe
+
+import "testing"
+
+func TestAnalyzeLifespan(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Area
+		b        Boundary
+		maxSteps int
+		want     Lifespan
+	}{
+		{"empty", createArea(5, 5), BoundaryNone, 0,
+			Lifespan{FinishReason: FinishReasonExtinct}},
+		{"block", newTestArea(4, 4, [][]int{{1, 1}, {2, 1}, {1, 2}, {2, 2}}), BoundaryNone, 0,
+			Lifespan{Period: 1, FinalPopulation: 4, PeakPopulation: 4, FinishReason: FinishReasonStable}},
+		{"blinker", newTestArea(5, 5, [][]int{{1, 2}, {2, 2}, {3, 2}}), BoundaryNone, 0,
+			Lifespan{Period: 2, FinalPopulation: 3, PeakPopulation: 3, FinishReason: FinishReasonCycle}},
+		{"pre-block", newTestArea(4, 4, [][]int{{1, 1}, {2, 1}, {1, 2}}), BoundaryNone, 0,
+			Lifespan{Generations: 1, Period: 1, FinalPopulation: 4, PeakPopulation: 4, PeakGeneration: 1, FinishReason: FinishReasonStable}},
+		{"dying pair", newTestArea(4, 4, [][]int{{1, 1}, {2, 1}}), BoundaryNone, 0,
+			Lifespan{Generations: 1, PeakPopulation: 2, FinishReason: FinishReasonExtinct}},
+		{"glider on torus", newTestArea(8, 8, [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}), BoundaryTorus, 0,
+			Lifespan{Period: 32, FinalPopulation: 5, PeakPopulation: 5, FinishReason: FinishReasonCycle}},
+		{"steps limit", newTestArea(8, 8, [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}), BoundaryTorus, 10,
+			Lifespan{Generations: 10, FinalPopulation: 5, PeakPopulation: 5, FinishReason: FinishReasonMaxSteps}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AnalyzeLifespan(tt.a, ConwayRule, tt.b, tt.maxSteps); got != tt.want {
+				t.Errorf("AnalyzeLifespan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeLifespan_Diehard(t *testing.T) {
+	a := newTestArea(40, 30, [][]int{{26, 12}, {20, 13}, {21, 13}, {21, 14}, {25, 14}, {26, 14}, {27, 14}})
+	got := AnalyzeLifespan(a, ConwayRule, BoundaryNone, 0)
+	if got.FinishReason != FinishReasonExtinct || got.Generations != 130 {
+		t.Errorf("AnalyzeLifespan() = %+v, want extinct at 130", got)
+	}
+}
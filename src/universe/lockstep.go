@@ -0,0 +1,29 @@
+package universe
+
+import (
+	"sync"
+)
+
+//StepAll does one step of all the not finished universes in parallel and returns when all the steps are done
+//it lets the external driver (e.g. the shared ticker) keep the universes on the same generation
+//returns true if all the universes are finished
+func StepAll(universes []Universe) (finished bool) {
+	wg := sync.WaitGroup{}
+	for _, u := range universes {
+		if u.Status().RunningMode == RunningStateFinished {
+			continue
+		}
+		wg.Add(1)
+		go func(u Universe) {
+			defer wg.Done()
+			u.StepSync()
+		}(u)
+	}
+	wg.Wait()
+	for _, u := range universes {
+		if u.Status().RunningMode != RunningStateFinished {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package universe
+
+import (
+	"testing"
+)
+
+func TestStepAll(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height, o.MaxSteps = 10, 10, 3
+	universes := make([]Universe, 0)
+	for _, e := range engineNames() {
+		u := engines[e](o, nil)
+		defer u.Close()
+		u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+		universes = append(universes, u)
+	}
+	//the single cell dies out on the first step and is not stepped anymore
+	lonely := newBaseUniverse(o, nil)
+	defer lonely.Close()
+	lonely.Settle([][]int{{5, 5}})
+	universes = append(universes, lonely)
+
+	for step := 1; step <= o.MaxSteps; step++ {
+		finished := StepAll(universes)
+		if finished != (step == o.MaxSteps) {
+			t.Errorf("step %d: got finished %v", step, finished)
+		}
+		for i, u := range universes[:len(universes)-1] {
+			if st := u.Status(); st.IterationNum != step {
+				t.Errorf("step %d: universe %d is on iteration %d", step, i, st.IterationNum)
+			}
+		}
+	}
+	if st := lonely.Status(); st.IterationNum != 1 || st.FinishReason != FinishReasonExtinct {
+		t.Errorf("got the lonely cell on iteration %d finished by %q, want 1 and %q", st.IterationNum, st.FinishReason, FinishReasonExtinct)
+	}
+}
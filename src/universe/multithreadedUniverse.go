@@ -45,10 +45,15 @@ func newWorkArea(x1 int, y1 int, x2 int, y2 int) workArea {
 	}
 }
 
-func NewMultithreadedUniverse(o *Options, stateCh chan Status) Universe {
-	mu := MultithreadedUniverse{BaseUniverse: NewBaseUniverse(o, stateCh)}
+func NewMultithreadedUniverse(o *Options, stateCh chan Status) (Universe, error) {
+	bu, err := NewBaseUniverse(o, stateCh)
+	if err != nil {
+		return nil, err
+	}
+	mu := MultithreadedUniverse{BaseUniverse: bu}
 	//redefine the nextIteration
 	mu.BaseUniverse.nextIteration = mu.nextIteration
+	mu.BaseUniverse.memoryUsage = mu.memoryUsage
 
 	mu.workers = DefWorkers
 	linesPerWorker := mu.area.Height / mu.workers
@@ -69,12 +74,16 @@ func NewMultithreadedUniverse(o *Options, stateCh chan Status) Universe {
 	mu.options.Advanced["engine"] = "multithreaded"
 	mu.options.Advanced["Workers"] = mu.workers
 	mu.options.Advanced["Rows per worker"] = linesPerWorker
-	return &mu
+	return &mu, nil
 }
 
 //nextIteration calcualtes next state for the universe
 //starts goroutines, waiting for finishing and update all related metrics
 func (mu *MultithreadedUniverse) nextIteration() (hasLiveEntities bool, changed bool) {
+	if mu.rule.Colors > 0 {
+		//the work areas don't keep the colors
+		return mu._nextIteration()
+	}
 	mu.area.Lock()
 	defer mu.area.Unlock()
 	start := time.Now()
@@ -94,8 +103,10 @@ func (mu *MultithreadedUniverse) nextIteration() (hasLiveEntities bool, changed
 		liveCells += workArea.liveCells
 		changed = changed || workArea.changed
 	}
+	mu.state.Lock()
 	mu.state.LiveCells = liveCells
 	mu.state.IterationTime = time.Since(start)
+	mu.state.Unlock()
 	hasLiveEntities = liveCells > 0
 	return
 }
@@ -103,7 +114,7 @@ func (mu *MultithreadedUniverse) nextIteration() (hasLiveEntities bool, changed
 //writeArea writes workArea buffer to Universe's area buffer
 func (mu *MultithreadedUniverse) writeArea(wa workArea) {
 	for y := range wa.tmpBuff.Entities {
-		copy(mu.area.Entities[wa.y1+y][wa.x1:wa.x2], wa.tmpBuff.Entities[y])
+		copy(mu.area.Entities[wa.y1+y][wa.x1:wa.x2+1], wa.tmpBuff.Entities[y])
 	}
 }
 
@@ -122,3 +133,12 @@ func (mu *MultithreadedUniverse) calcArea(wa *workArea) {
 		}
 	}
 }
+
+//memoryUsage estimates the bytes of the area and the workers buffers
+func (mu *MultithreadedUniverse) memoryUsage() int {
+	bytes := areaBytes(mu.area.Area)
+	for _, wa := range mu.workAreas {
+		bytes += areaBytes(wa.tmpBuff)
+	}
+	return bytes
+}
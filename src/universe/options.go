@@ -0,0 +1,149 @@
+package universe
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidOptions = errors.New("invalid options")
+)
+
+//SetOptions validates the options and applies them to the running universe
+//the dimensions can't be changed, the advanced options are merged with the current ones
+//returns ErrInvalidOptions if any value is invalid, nothing is changed in this case
+func (u *BaseUniverse) SetOptions(o Options) error {
+	if err := u.validateOptions(o); err != nil {
+		return err
+	}
+	u.updateOptions(func() {
+		u.options.Interval = o.Interval
+		u.options.MaxSteps = o.MaxSteps
+		u.options.MaxSkippedTicks = o.MaxSkippedTicks
+		for k, v := range o.Advanced {
+			u.setAdvanced(k, v)
+		}
+	})
+	return nil
+}
+
+//SetInterval changes the interval between the steps
+func (u *BaseUniverse) SetInterval(d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("%w: the interval can't be negative", ErrInvalidOptions)
+	}
+	u.updateOptions(func() {
+		u.options.Interval = d
+	})
+	return nil
+}
+
+//SetMaxSteps changes the steps limit, 0 - unlimited
+func (u *BaseUniverse) SetMaxSteps(n int) error {
+	if n < 0 {
+		return fmt.Errorf("%w: the steps limit can't be negative", ErrInvalidOptions)
+	}
+	u.updateOptions(func() {
+		u.options.MaxSteps = n
+	})
+	return nil
+}
+
+//SetBoundary changes the edges behaviour
+func (u *BaseUniverse) SetBoundary(b Boundary) error {
+	return u.setAdvancedOption(AdvBoundary, string(b))
+}
+
+//SetDensity changes the share of the live cells in the random areas
+func (u *BaseUniverse) SetDensity(d float64) error {
+	return u.setAdvancedOption(AdvDensity, d)
+}
+
+//setAdvancedOption validates and applies the single advanced option
+func (u *BaseUniverse) setAdvancedOption(name string, value interface{}) error {
+	if err := validateAdvanced(name, value); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidOptions, name, err)
+	}
+	u.updateOptions(func() {
+		u.setAdvanced(name, value)
+	})
+	return nil
+}
+
+//updateOptions changes the options by the main loop under the state lock
+//the rule and the boundary are updated from the advanced options and the views are refreshed
+func (u *BaseUniverse) updateOptions(update func()) {
+	u.controlCh <- func() {
+		u.state.Lock()
+		update()
+		if r, err := ParseRule(u.advancedString(AdvRule, "")); err == nil {
+			u.rule = r
+			u.setAdvanced(AdvRule, r.String())
+		}
+		u.boundary, _ = ParseBoundary(u.advancedString(AdvBoundary, ""))
+		u.wrapX, u.wrapY = u.boundary.WrapX(), u.boundary.WrapY()
+		u.state.Unlock()
+		u.applyColors()
+		u.refreshView()
+	}
+}
+
+//validateOptions checks if the options can be applied to the universe
+func (u *BaseUniverse) validateOptions(o Options) error {
+	cur := u.Options()
+	if o.Width != cur.Width || o.Height != cur.Height {
+		return fmt.Errorf("%w: the dimensions can't be changed at runtime", ErrInvalidOptions)
+	}
+	if o.Interval < 0 || o.MaxSteps < 0 || o.MaxSkippedTicks < 0 {
+		return fmt.Errorf("%w: the interval and the limits can't be negative", ErrInvalidOptions)
+	}
+	for k, v := range o.Advanced {
+		if err := validateAdvanced(k, v); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidOptions, k, err)
+		}
+	}
+	return nil
+}
+
+//validateAdvanced checks the value of the advanced option known by the universe, the other options are accepted as is
+func validateAdvanced(name string, value interface{}) error {
+	switch name {
+	case AdvRule:
+		s, _ := value.(string)
+		_, err := ParseRule(s)
+		return err
+	case AdvBoundary:
+		if s, _ := value.(string); s != "" {
+			if _, ok := ParseBoundary(s); ok {
+				return nil
+			}
+		}
+		return errors.New("unknown boundary")
+	case AdvOnMaxSteps:
+		switch value {
+		case OnMaxStepsStop, OnMaxStepsContinue, OnMaxStepsReset:
+			return nil
+		}
+		return errors.New("unknown value")
+	case AdvMaxCells, AdvMaxPeriod, AdvStepBudget, AdvHistory, AdvHistoryMB:
+		if n, ok := value.(int); !ok || n < 0 {
+			return errors.New("should be a non-negative integer")
+		}
+	case AdvDensity:
+		if d, ok := value.(float64); !ok || d < 0 || d > 1 {
+			return errors.New("should be in range [0, 1]")
+		}
+	case AdvOrigin:
+		switch value {
+		case OriginTopLeft, OriginBottomLeft:
+			return nil
+		}
+		return errors.New("unknown origin")
+	case AdvAutostart:
+		if _, ok := value.(bool); !ok {
+			return errors.New("should be a boolean")
+		}
+	}
+	return nil
+}
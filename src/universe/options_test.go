@@ -0,0 +1,58 @@
+package universe
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBaseUniverse_SetOptions(t *testing.T) {
+	stateCh := newStateCh()
+	u := newBaseUniverse(newUniverseOptions(), stateCh)
+	defer u.Close()
+	invalid := []struct {
+		name   string
+		modify func(o *Options)
+	}{
+		{"dimensions", func(o *Options) { o.Width++ }},
+		{"negative interval", func(o *Options) { o.Interval = -time.Second }},
+		{"negative max steps", func(o *Options) { o.MaxSteps = -1 }},
+		{"bad rule", func(o *Options) { o.Advanced = map[string]interface{}{AdvRule: "B3"} }},
+		{"bad boundary", func(o *Options) { o.Advanced = map[string]interface{}{AdvBoundary: "sphere"} }},
+		{"bad density", func(o *Options) { o.Advanced = map[string]interface{}{AdvDensity: 1.5} }},
+		{"bad max cells type", func(o *Options) { o.Advanced = map[string]interface{}{AdvMaxCells: "10"} }},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			o := u.Options()
+			tt.modify(&o)
+			if err := u.SetOptions(o); !errors.Is(err, ErrInvalidOptions) {
+				t.Errorf("got error %v, want %v", err, ErrInvalidOptions)
+			}
+		})
+	}
+
+	o := u.Options()
+	o.Advanced = map[string]interface{}{AdvRule: "b36/s23"}
+	if err := u.SetOptions(o); err != nil {
+		t.Fatal(err)
+	}
+	//the field setters don't overwrite the pending changes
+	if err := u.SetMaxSteps(7); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.SetBoundary(BoundaryTorus); err != nil {
+		t.Fatal(err)
+	}
+	//the options are applied by the main loop before the step
+	u.Settle([][]int{{4, 5}, {5, 5}, {6, 5}}) //blinker
+	u.Step()
+	waitMode(stateCh, RunningStateManual)
+	got := u.Options()
+	if got.MaxSteps != 7 || u.Boundary() != BoundaryTorus || u.Rule().String() != "B36/S23" {
+		t.Errorf("got max steps %v, boundary %v, rule %v", got.MaxSteps, u.Boundary(), u.Rule())
+	}
+	if got.Advanced["engine"] != "base" {
+		t.Errorf("the advanced options are not merged: %v", got.Advanced)
+	}
+}
@@ -0,0 +1,34 @@
+package universe
+
+import "math/rand"
+
+//fleetSpacing is the side of the square slot of the glider in the fleet, the gliders in the neighbour slots never touch
+const fleetSpacing = 6
+
+var (
+	//Glider is the smallest spaceship, it moves by one cell to the bottom right every 4 steps
+	Glider = Template{
+		Name:        "glider",
+		Descr:       "the smallest spaceship heading to the bottom right",
+		Coordinates: [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}},
+	}
+)
+
+//GliderFleet returns the template of the gliders heading to the bottom right in the random slots of the width x height field
+//the slots are chosen by the seeded random generator, density is the share of the occupied slots
+//all the gliders move in the same phase, so they never collide, also on the wrapped field
+func GliderFleet(width int, height int, density float64, seed int64) Template {
+	r := rand.New(rand.NewSource(seed))
+	fleet := Template{Name: "glider fleet", Descr: "the gliders heading to the bottom right", Coordinates: make([][]int, 0)}
+	for y := 0; y+fleetSpacing <= height; y += fleetSpacing {
+		for x := 0; x+fleetSpacing <= width; x += fleetSpacing {
+			if r.Float64() >= density {
+				continue
+			}
+			for _, c := range Glider.Coordinates {
+				fleet.Coordinates = append(fleet.Coordinates, []int{x + c[0], y + c[1]})
+			}
+		}
+	}
+	return fleet
+}
@@ -0,0 +1,29 @@
+package universe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGliderFleet(t *testing.T) {
+	fleet := GliderFleet(64, 40, 0.5, 1)
+	if len(fleet.Coordinates) == 0 || len(fleet.Coordinates)%len(Glider.Coordinates) != 0 {
+		t.Fatalf("got %v cells, want the whole gliders", len(fleet.Coordinates))
+	}
+	if again := GliderFleet(64, 40, 0.5, 1); !reflect.DeepEqual(again, fleet) {
+		t.Error("the fleet differs for the same seed")
+	}
+	//the fleet moves by one cell to the bottom right every 4 steps around the torus without collisions
+	a := newTestArea(64, 40, fleet.Coordinates)
+	next := a
+	for i := 0; i < 4*64; i++ {
+		next = nextGeneration(next, ConwayRule, BoundaryTorus)
+	}
+	shifted := make([][]int, 0, len(fleet.Coordinates))
+	for _, c := range fleet.Coordinates {
+		shifted = append(shifted, []int{(c[0] + 64) % 64, (c[1] + 64) % 40})
+	}
+	if d, _ := Diff(newTestArea(64, 40, shifted), next); len(d) != 0 {
+		t.Errorf("the fleet differs from the shifted one in %v cells", len(d))
+	}
+}
@@ -0,0 +1,81 @@
+package universe
+
+import (
+	"errors"
+)
+
+//DefMaxPredecessorCells is the largest area (in cells) the predecessor search is allowed for
+const DefMaxPredecessorCells = 64
+
+var (
+	ErrAreaTooLarge = errors.New("the area is too large for the predecessor search")
+)
+
+//HasPredecessor checks if the area could be the result of a step from some other area of the same size by the Conway's rule
+//the cells outside the area are considered dead as they are in the universe
+//the area without predecessor is a Garden of Eden
+//the search is exponential, so it returns ErrAreaTooLarge for the areas larger than DefMaxPredecessorCells
+func HasPredecessor(a Area) (bool, error) {
+	cells := a.Width * a.Height
+	if cells > DefMaxPredecessorCells {
+		return false, ErrAreaTooLarge
+	}
+	if cells <= 0 {
+		return true, nil
+	}
+	s := predecessorSearch{
+		target: a,
+		parent: createArea(a.Width, a.Height),
+		checks: make([][]Point, cells),
+	}
+	//each cell is checked as soon as its last (in row-major order) neighbour is assigned
+	for y := 0; y < a.Height; y++ {
+		for x := 0; x < a.Width; x++ {
+			lx, ly := x+1, y+1
+			if lx >= a.Width {
+				lx = a.Width - 1
+			}
+			if ly >= a.Height {
+				ly = a.Height - 1
+			}
+			last := ly*a.Width + lx
+			s.checks[last] = append(s.checks[last], Point{x, y})
+		}
+	}
+	return s.search(0), nil
+}
+
+//predecessorSearch is the backtracking search state
+type predecessorSearch struct {
+	target Area
+	parent Area
+	checks [][]Point //the cells which next state is known when the cell with the index is assigned
+}
+
+//search assigns the parent cells starting from the index i in row-major order
+//returns true if the assignment leading to the target is found
+func (s *predecessorSearch) search(i int) bool {
+	if i == len(s.checks) {
+		return true
+	}
+	x, y := i%s.parent.Width, i/s.parent.Width
+	for _, state := range []Cell{false, true} {
+		s.parent.Entities[y][x] = state
+		if s.consistent(i) && s.search(i+1) {
+			return true
+		}
+	}
+	s.parent.Entities[y][x] = false
+	return false
+}
+
+//consistent checks the cells which next state is determined by the assignment of the cell with the index i
+func (s *predecessorSearch) consistent(i int) bool {
+	for _, p := range s.checks[i] {
+		next := ConwayRule.nextState(bool(s.parent.Entities[p.Y][p.X]), liveNeighbours(s.parent, p.X, p.Y, false, false))
+		if next != bool(s.target.Entities[p.Y][p.X]) {
+			return false
+		}
+	}
+	return true
+}
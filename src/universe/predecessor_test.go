@@ -0,0 +1,50 @@
+package universe
+
+import (
+	"testing"
+)
+
+//areaFromBits creates the area with the cells set from the bits in row-major order
+func areaFromBits(width int, height int, bits int) Area {
+	a := createArea(width, height)
+	for i := 0; i < width*height; i++ {
+		a.Entities[i/width][i%width] = bits&(1<<uint(i)) != 0
+	}
+	return a
+}
+
+func TestHasPredecessor(t *testing.T) {
+	const width, height = 3, 3
+	//all the 3x3 areas reachable by one step
+	reachable := make(map[int]bool)
+	for bits := 0; bits < 1<<(width*height); bits++ {
+		next := nextGeneration(areaFromBits(width, height, bits), ConwayRule, BoundaryNone)
+		key := 0
+		for i := 0; i < width*height; i++ {
+			if next.Entities[i/width][i%width] {
+				key |= 1 << uint(i)
+			}
+		}
+		reachable[key] = true
+	}
+	gardens := 0
+	for bits := 0; bits < 1<<(width*height); bits++ {
+		got, err := HasPredecessor(areaFromBits(width, height, bits))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != reachable[bits] {
+			t.Errorf("HasPredecessor(%09b) = %v, want %v", bits, got, reachable[bits])
+		}
+		if !got {
+			gardens++
+		}
+	}
+	if gardens == 0 {
+		t.Error("no Garden of Eden found among 3x3 areas")
+	}
+
+	if _, err := HasPredecessor(createArea(10, 10)); err != ErrAreaTooLarge {
+		t.Errorf("HasPredecessor() of the large area error = %v, want %v", err, ErrAreaTooLarge)
+	}
+}
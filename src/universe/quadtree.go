@@ -0,0 +1,294 @@
+package universe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unsafe"
+)
+
+/*
+	Canonical quadtree used by the hashlife engine
+	Every node is unique in the table: the equal squares are represented by the same node,
+	so the results of the node evolution can be memoized in the node itself
+*/
+
+//DefMaxQuadNodes is the node table size causing the table flush and stopping the jumps calculation
+const DefMaxQuadNodes = 1 << 20
+
+//QuadNode is the square of 2^Level x 2^Level cells
+//the level 0 nodes are the single cells
+type QuadNode struct {
+	NW, NE, SW, SE *QuadNode
+	Level          int
+	Population     int
+	Alive          bool        //the cell state for the level 0 nodes
+	results        []*QuadNode //the centre advanced by 2^k generations, indexed by k
+}
+
+//mcLeafLevel is the level of the macrocell leaves written as the cells
+const mcLeafLevel = 3
+
+//quadKey identifies the node by its children
+type quadKey struct {
+	nw, ne, sw, se *QuadNode
+}
+
+//quadTree is the table of the canonical nodes with the rule the results are memoized for
+type quadTree struct {
+	rule     Rule
+	nodes    map[quadKey]*QuadNode
+	maxNodes int         //advance gives up when the table grows beyond, 0 - unlimited
+	empty    []*QuadNode //the empty nodes indexed by the level
+	dead     *QuadNode
+	live     *QuadNode
+}
+
+//newQuadTree creates the empty node table for the rule
+func newQuadTree(r Rule) *quadTree {
+	t := quadTree{
+		rule:  r,
+		nodes: make(map[quadKey]*QuadNode),
+		dead:  &QuadNode{},
+		live:  &QuadNode{Alive: true, Population: 1},
+	}
+	t.empty = []*QuadNode{t.dead}
+	return &t
+}
+
+//cell returns the level 0 node
+func (t *quadTree) cell(alive bool) *QuadNode {
+	if alive {
+		return t.live
+	}
+	return t.dead
+}
+
+//join returns the canonical node with the children
+func (t *quadTree) join(nw, ne, sw, se *QuadNode) *QuadNode {
+	k := quadKey{nw, ne, sw, se}
+	if n, ok := t.nodes[k]; ok {
+		return n
+	}
+	n := &QuadNode{
+		NW:         nw,
+		NE:         ne,
+		SW:         sw,
+		SE:         se,
+		Level:      nw.Level + 1,
+		Population: nw.Population + ne.Population + sw.Population + se.Population,
+	}
+	t.nodes[k] = n
+	return n
+}
+
+//memoryUsage estimates the bytes of the nodes table, the memoized results are not counted
+func (t *quadTree) memoryUsage() int {
+	entry := unsafe.Sizeof(quadKey{}) + unsafe.Sizeof(&QuadNode{}) + unsafe.Sizeof(QuadNode{})
+	return len(t.nodes) * int(entry)
+}
+
+//emptyNode returns the node without live cells
+func (t *quadTree) emptyNode(level int) *QuadNode {
+	for len(t.empty) <= level {
+		e := t.empty[len(t.empty)-1]
+		t.empty = append(t.empty, t.join(e, e, e, e))
+	}
+	return t.empty[level]
+}
+
+//centre returns the central subnode with the half size
+func (t *quadTree) centre(n *QuadNode) *QuadNode {
+	return t.join(n.NW.SE, n.NE.SW, n.SW.NE, n.SE.NW)
+}
+
+//fromArea builds the node of the level with the area placed at the offset x, y
+func (t *quadTree) fromArea(a Area, level int, x int, y int) *QuadNode {
+	size := 1 << uint(level)
+	if x >= a.Width || y >= a.Height || x+size <= 0 || y+size <= 0 {
+		return t.emptyNode(level)
+	}
+	if level == 0 {
+		return t.cell(bool(a.Entities[y][x]))
+	}
+	half := size / 2
+	return t.join(
+		t.fromArea(a, level-1, x, y),
+		t.fromArea(a, level-1, x+half, y),
+		t.fromArea(a, level-1, x, y+half),
+		t.fromArea(a, level-1, x+half, y+half),
+	)
+}
+
+//walkLive calls cb for the each live cell of the node placed at x, y
+func (t *quadTree) walkLive(n *QuadNode, x int, y int, cb func(x int, y int)) {
+	if n.Population == 0 {
+		return
+	}
+	if n.Level == 0 {
+		cb(x, y)
+		return
+	}
+	half := 1 << uint(n.Level-1)
+	t.walkLive(n.NW, x, y, cb)
+	t.walkLive(n.NE, x+half, y, cb)
+	t.walkLive(n.SW, x, y+half, cb)
+	t.walkLive(n.SE, x+half, y+half, cb)
+}
+
+//advance returns the centre of the node advanced by 2^k generations, k should be in range [0, n.Level-2]
+//the cells outside the node are ignored, which gives the exact result for the centre
+//returns nil if the nodes table grows beyond maxNodes, the results calculated so far are kept
+func (t *quadTree) advance(n *QuadNode, k int) *QuadNode {
+	if n.Population == 0 {
+		return t.emptyNode(n.Level - 1)
+	}
+	if n.results == nil {
+		n.results = make([]*QuadNode, n.Level-1)
+	}
+	if r := n.results[k]; r != nil {
+		return r
+	}
+	if t.maxNodes > 0 && len(t.nodes) > t.maxNodes {
+		return nil
+	}
+	var r *QuadNode
+	if n.Level == 2 {
+		r = t.advanceBase(n)
+	} else {
+		//the 9 overlapping subnodes with the half size
+		m := [3][3]*QuadNode{
+			{n.NW, t.join(n.NW.NE, n.NE.NW, n.NW.SE, n.NE.SW), n.NE},
+			{t.join(n.NW.SW, n.NW.SE, n.SW.NW, n.SW.NE), t.centre(n), t.join(n.NE.SW, n.NE.SE, n.SE.NW, n.SE.NE)},
+			{n.SW, t.join(n.SW.NE, n.SE.NW, n.SW.SE, n.SE.SW), n.SE},
+		}
+		var s [3][3]*QuadNode
+		full := k == n.Level-2
+		for i := range m {
+			for j := range m[i] {
+				if full {
+					//the first half of the jump
+					if s[i][j] = t.advance(m[i][j], k-1); s[i][j] == nil {
+						return nil
+					}
+				} else {
+					s[i][j] = t.centre(m[i][j])
+				}
+			}
+		}
+		//the full jump takes the rest half, the shorter one is done at once
+		rk := k
+		if full {
+			rk = k - 1
+		}
+		var q [4]*QuadNode
+		for i, c := range [4]*QuadNode{
+			t.join(s[0][0], s[0][1], s[1][0], s[1][1]),
+			t.join(s[0][1], s[0][2], s[1][1], s[1][2]),
+			t.join(s[1][0], s[1][1], s[2][0], s[2][1]),
+			t.join(s[1][1], s[1][2], s[2][1], s[2][2]),
+		} {
+			if q[i] = t.advance(c, rk); q[i] == nil {
+				return nil
+			}
+		}
+		r = t.join(q[0], q[1], q[2], q[3])
+	}
+	n.results[k] = r
+	return r
+}
+
+//advanceBase calculates the next generation of the 2x2 centre of the 4x4 node
+func (t *quadTree) advanceBase(n *QuadNode) *QuadNode {
+	var c [4][4]bool
+	t.walkLive(n, 0, 0, func(x int, y int) {
+		c[y][x] = true
+	})
+	next := func(x int, y int) *QuadNode {
+		neighbours := 0
+		for i := -1; i < 2; i++ {
+			for j := -1; j < 2; j++ {
+				if (i != 0 || j != 0) && c[y+i][x+j] {
+					neighbours++
+				}
+			}
+		}
+		return t.cell(t.rule.nextState(c[y][x], neighbours))
+	}
+	return t.join(next(1, 1), next(2, 1), next(1, 2), next(2, 2))
+}
+
+//SaveMC writes the area as Golly's macrocell pattern with the rule, the area is placed at the top left corner of the root node
+func SaveMC(w io.Writer, a Area, r Rule) error {
+	level := mcLeafLevel
+	for 1<<uint(level) < a.Width || 1<<uint(level) < a.Height {
+		level++
+	}
+	return saveMC(w, newQuadTree(r).fromArea(a, level, 0, 0), r)
+}
+
+//saveMC writes the node as the macrocell pattern, the equal subnodes are written once
+//the 8x8 squares are the leaves of the cells rows, the larger nodes refer to the children by the line numbers, 0 is the empty node
+func saveMC(w io.Writer, tree *QuadNode, r Rule) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "[M2] (simlife)")
+	_, _ = fmt.Fprintf(bw, "#R %v\n", r)
+	ids := make(map[*QuadNode]int)
+	var write func(n *QuadNode, root bool) int
+	write = func(n *QuadNode, root bool) int {
+		if n.Population == 0 && !root {
+			return 0
+		}
+		if id, ok := ids[n]; ok {
+			return id
+		}
+		if n.Level <= mcLeafLevel {
+			_, _ = fmt.Fprintln(bw, mcLeaf(n))
+		} else {
+			nw, ne, sw, se := write(n.NW, false), write(n.NE, false), write(n.SW, false), write(n.SE, false)
+			_, _ = fmt.Fprintf(bw, "%d %d %d %d %d\n", n.Level, nw, ne, sw, se)
+		}
+		ids[n] = len(ids) + 1
+		return ids[n]
+	}
+	write(tree, true)
+	return bw.Flush()
+}
+
+//mcLeaf returns the macrocell leaf line of the node up to the leaf level, the trailing dead cells and rows are omitted
+func mcLeaf(n *QuadNode) string {
+	var cells [1 << mcLeafLevel][1 << mcLeafLevel]bool
+	var walk func(n *QuadNode, x int, y int)
+	walk = func(n *QuadNode, x int, y int) {
+		switch {
+		case n.Population == 0:
+		case n.Level == 0:
+			cells[y][x] = true
+		default:
+			half := 1 << uint(n.Level-1)
+			walk(n.NW, x, y)
+			walk(n.NE, x+half, y)
+			walk(n.SW, x, y+half)
+			walk(n.SE, x+half, y+half)
+		}
+	}
+	walk(n, 0, 0)
+	rows := make([]string, len(cells))
+	for y := range cells {
+		row := ""
+		for x := range cells[y] {
+			if cells[y][x] {
+				row += "*"
+			} else {
+				row += "."
+			}
+		}
+		rows[y] = strings.TrimRight(row, ".") + "$"
+	}
+	last := len(rows) - 1
+	for last > 0 && rows[last] == "$" {
+		last--
+	}
+	return strings.Join(rows[:last+1], "")
+}
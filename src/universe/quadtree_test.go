@@ -0,0 +1,134 @@
+package universe
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSaveMC(t *testing.T) {
+	glider := [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	cases := []struct {
+		name   string
+		width  int
+		height int
+		cells  [][]int
+		want   string
+	}{
+		{"empty", 8, 8, nil, "[M2] (simlife)\n#R B3/S23\n$\n"},
+		{"leaf", 8, 8, glider, "[M2] (simlife)\n#R B3/S23\n.*$..*$***$\n"},
+		//the equal leaves are written once
+		{"shared", 16, 16, append(glider, []int{9, 0}, []int{10, 1}, []int{8, 2}, []int{9, 2}, []int{10, 2}),
+			"[M2] (simlife)\n#R B3/S23\n.*$..*$***$\n4 1 1 0 0\n"},
+		//the area is placed in the root node of the larger side
+		{"wide", 12, 5, [][]int{{11, 4}}, "[M2] (simlife)\n#R B3/S23\n$$$$...*$\n4 0 1 0 0\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := SaveMC(&b, newTestArea(c.width, c.height, c.cells), ConwayRule); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != c.want {
+				t.Errorf("got %q, want %q", b.String(), c.want)
+			}
+		})
+	}
+}
+
+func TestSaveMC_RoundTrip(t *testing.T) {
+	r, err := ParseRule("B36/S23")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := RandomArea(40, 30, 0.3, 1)
+	var b bytes.Buffer
+	if err := SaveMC(&b, a, r); err != nil {
+		t.Fatal(err)
+	}
+	rule, cells, err := parseMC(b.String())
+	if err != nil {
+		t.Fatalf("%v\n%s", err, b.String())
+	}
+	if rule != "B36/S23" {
+		t.Errorf("rule %q, want B36/S23", rule)
+	}
+	var want []Point
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			if a.Entities[y][x] {
+				want = append(want, Point{x, y})
+			}
+		}
+	}
+	if !reflect.DeepEqual(cells, want) {
+		t.Errorf("got %d cells, want %d: %v", len(cells), len(want), cells)
+	}
+}
+
+//parseMC reads the rule and the live cells sorted by the rows of the macrocell pattern
+func parseMC(s string) (rule string, cells []Point, err error) {
+	type mcNode struct {
+		level          int
+		leaf           string
+		nw, ne, sw, se int
+	}
+	nodes := []mcNode{{}}
+	for i, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		switch {
+		case i == 0:
+			if !strings.HasPrefix(line, "[M2]") {
+				return "", nil, fmt.Errorf("no header: %q", line)
+			}
+		case strings.HasPrefix(line, "#R "):
+			rule = strings.TrimPrefix(line, "#R ")
+		case strings.HasPrefix(line, "#"):
+		case strings.ContainsAny(line, ".*$"):
+			nodes = append(nodes, mcNode{level: mcLeafLevel, leaf: line})
+		default:
+			var n mcNode
+			if _, err := fmt.Sscanf(line, "%d %d %d %d %d", &n.level, &n.nw, &n.ne, &n.sw, &n.se); err != nil {
+				return "", nil, fmt.Errorf("line %d: %v", i, err)
+			}
+			for _, c := range []int{n.nw, n.ne, n.sw, n.se} {
+				if c >= len(nodes) {
+					return "", nil, fmt.Errorf("line %d: forward reference %d", i, c)
+				}
+			}
+			nodes = append(nodes, n)
+		}
+	}
+	var walk func(id int, x int, y int)
+	walk = func(id int, x int, y int) {
+		n := nodes[id]
+		if id == 0 {
+			return
+		}
+		if n.leaf != "" {
+			for dy, row := range strings.Split(n.leaf, "$") {
+				for dx, c := range row {
+					if c == '*' {
+						cells = append(cells, Point{x + dx, y + dy})
+					}
+				}
+			}
+			return
+		}
+		half := 1 << uint(n.level-1)
+		walk(n.nw, x, y)
+		walk(n.ne, x+half, y)
+		walk(n.sw, x, y+half)
+		walk(n.se, x+half, y+half)
+	}
+	walk(len(nodes)-1, 0, 0)
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y != cells[j].Y {
+			return cells[i].Y < cells[j].Y
+		}
+		return cells[i].X < cells[j].X
+	})
+	return rule, cells, nil
+}
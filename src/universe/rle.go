@@ -0,0 +1,315 @@
+package universe
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//RLE is the pattern in the run length encoded format with its metadata
+type RLE struct {
+	Name        string   //#N line
+	Author      string   //#O line
+	Comments    []string //#C lines
+	Rule        string   //the rule from the header without the bounded grid suffix, empty if not set
+	Boundary    Boundary //the edges behaviour from the bounded grid suffix of the rule, empty if not set
+	FieldWidth  int      //the field size from the bounded grid suffix of the rule, 0 if not set or unbounded
+	FieldHeight int
+	Width       int
+	Height      int
+	X           int //the top left corner from the #P or #R line, 0 if not set
+	Y           int
+	Coordinates [][]int //array of [x,y] coordinates of the live cells
+}
+
+//rleLineLength is the maximal length of the pattern line written by SaveRLE, the runs are never split between the lines
+const rleLineLength = 70
+
+//maxRLERun is the maximal run count read by LoadRLE, the larger ones are rejected rather than overflowed
+const maxRLERun = 1 << 30
+
+var (
+	ErrInvalidRLE = errors.New("invalid RLE")
+)
+
+//LoadRLE reads the first pattern of the RLE file with the #-prefixed metadata
+//returns the warnings about the ignored content and ErrInvalidRLE with the line number for the malformed files,
+//the cells beyond the header size and the sizes larger than maxPatternCells are rejected
+func LoadRLE(r io.Reader) (p RLE, warnings []string, err error) {
+	s := bufio.NewScanner(r)
+	header := false
+	finished := false
+	x, y := 0, 0
+	//the run count may be split from its tag by the line break
+	count := 0
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		switch {
+		case text == "":
+		case strings.HasPrefix(text, "#"):
+			if !finished {
+				p.addMetadata(text)
+			}
+		case finished:
+			if strings.HasPrefix(text, "x") {
+				warnings = append(warnings, "the file contains multiple patterns, the first one is loaded")
+				return p, warnings, nil
+			}
+		case !header:
+			w, err := p.parseHeader(text)
+			if err != nil {
+				return p, warnings, fmt.Errorf("%w: line %d: %v", ErrInvalidRLE, line, err)
+			}
+			warnings = append(warnings, w...)
+			header = true
+		default:
+			for _, c := range text {
+				switch {
+				case c >= '0' && c <= '9':
+					if count > maxRLERun/10 {
+						return p, warnings, fmt.Errorf("%w: line %d: the run count is too large", ErrInvalidRLE, line)
+					}
+					count = count*10 + int(c-'0')
+					continue
+				case c == ' ' || c == '\t':
+					continue
+				case c == '!':
+					finished = true
+				case c == '$':
+					y += max(count, 1)
+					x = 0
+				case c == 'b' || c == '.':
+					x += max(count, 1)
+				case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+					//the multi-state cells are treated as live
+					if x+max(count, 1) > p.Width || y >= p.Height {
+						return p, warnings, fmt.Errorf("%w: line %d: the cells exceed the %d x %d size", ErrInvalidRLE, line, p.Width, p.Height)
+					}
+					for i := 0; i < max(count, 1); i++ {
+						p.Coordinates = append(p.Coordinates, []int{x, y})
+						x++
+					}
+				default:
+					return p, warnings, fmt.Errorf("%w: line %d: unexpected %q", ErrInvalidRLE, line, c)
+				}
+				count = 0
+				if finished {
+					break
+				}
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return p, warnings, err
+	}
+	if !header {
+		return p, warnings, fmt.Errorf("%w: the header is missing", ErrInvalidRLE)
+	}
+	if !finished {
+		warnings = append(warnings, "the pattern is not terminated by !")
+	}
+	return p, warnings, nil
+}
+
+//SaveRLE writes the live cells of the area cropped to their bounding box as the RLE pattern with the rule in the header
+//the rule is followed by Golly's bounded grid suffix of the area size and the boundary, see gridSuffix
+func SaveRLE(w io.Writer, a Area, r Rule, b Boundary) error {
+	x1, y1, x2, y2 := a.Width, a.Height, -1, -1
+	if lo, hi, ok := LiveBounds(a); ok {
+		x1, y1, x2, y2 = lo.X, lo.Y, hi.X, hi.Y
+	}
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "x = %d, y = %d, rule = %v%v\n", max(x2-x1+1, 0), max(y2-y1+1, 0), r, gridSuffix(b, a.Width, a.Height))
+	line := 0
+	//put adds the run to the output keeping the lines shorter than rleLineLength
+	put := func(count int, tag byte) {
+		run := string(tag)
+		if count > 1 {
+			run = strconv.Itoa(count) + run
+		}
+		if line+len(run) > rleLineLength {
+			_ = bw.WriteByte('\n')
+			line = 0
+		}
+		_, _ = bw.WriteString(run)
+		line += len(run)
+	}
+	newLines := 0
+	for y := y1; y <= y2; y++ {
+		count, tag := 0, byte(0)
+		for x := x1; x <= x2; x++ {
+			next := byte('b')
+			if a.Entities[y][x] {
+				next = 'o'
+			}
+			if next != tag && count > 0 {
+				if newLines > 0 {
+					put(newLines, '$')
+					newLines = 0
+				}
+				put(count, tag)
+				count = 0
+			}
+			tag = next
+			count++
+		}
+		//the trailing dead cells are omitted
+		if tag == 'o' {
+			if newLines > 0 {
+				put(newLines, '$')
+				newLines = 0
+			}
+			put(count, tag)
+		}
+		newLines++
+	}
+	put(1, '!')
+	_ = bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+//Template returns the seeding template of the pattern
+func (p RLE) Template() Template {
+	return Template{
+		Name:        p.Name,
+		Descr:       strings.Join(p.Comments, "\n"),
+		Coordinates: p.Coordinates,
+	}
+}
+
+//addMetadata parses the #-prefixed line, the unknown lines are ignored
+func (p *RLE) addMetadata(text string) {
+	if len(text) < 2 {
+		return
+	}
+	value := strings.TrimSpace(text[2:])
+	switch text[1] {
+	case 'N':
+		p.Name = value
+	case 'O':
+		p.Author = value
+	case 'C', 'c':
+		p.Comments = append(p.Comments, value)
+	case 'r':
+		p.Rule = value
+	case 'P', 'R':
+		if _, err := fmt.Sscan(value, &p.X, &p.Y); err != nil {
+			p.X, p.Y = 0, 0
+		}
+	}
+}
+
+//parseHeader parses the "x = m, y = n, rule = abc" line, returns the warnings about the ignored bounded grid suffix
+//the rule is the last field, so the comma of its bounded grid suffix is not a separator
+func (p *RLE) parseHeader(text string) (warnings []string, err error) {
+	fields := strings.Split(text, ",")
+	for i := 0; i < len(fields); i++ {
+		kv := strings.SplitN(fields[i], "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("malformed header field %q", fields[i])
+		}
+		k, v := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch k {
+		case "x", "y":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("the %s dimension should be a non-negative integer", k)
+			}
+			if k == "x" {
+				p.Width = n
+			} else {
+				p.Height = n
+			}
+		case "rule":
+			v = strings.TrimSpace(strings.Join(append([]string{v}, fields[i+1:]...), ","))
+			i = len(fields)
+			p.Rule = v
+			if j := strings.Index(v, ":"); j >= 0 {
+				p.Rule = strings.TrimSpace(v[:j])
+				w, err := p.parseGrid(strings.TrimSpace(v[j+1:]))
+				if err != nil {
+					return nil, err
+				}
+				if w != "" {
+					warnings = append(warnings, w)
+				}
+			}
+		}
+	}
+	if p.Width > maxPatternCells || p.Height > maxPatternCells || p.Width*p.Height > maxPatternCells {
+		return nil, fmt.Errorf("the %d x %d size is too large", p.Width, p.Height)
+	}
+	return warnings, nil
+}
+
+//parseGrid parses Golly's bounded grid suffix of the rule: P for the plane or T for the torus followed by the width and the height
+//the zero torus size is unbounded, so T<w>,0 wraps the left and right edges only and T0,<h> the top and bottom ones
+//the other grids (Klein bottle, cross-surface, sphere) and the shifted edges are ignored with the warning
+func (p *RLE) parseGrid(s string) (warning string, err error) {
+	if s == "" {
+		return "", errors.New("empty bounded grid")
+	}
+	kind := strings.ToUpper(s[:1])
+	if kind != "P" && kind != "T" || strings.ContainsAny(s, "+*") {
+		return fmt.Sprintf("the bounded grid %q is not supported, the plane is used", s), nil
+	}
+	size := strings.SplitN(s[1:], ",", 2)
+	if len(size) != 2 {
+		return "", fmt.Errorf("the bounded grid %q should have the width and the height", s)
+	}
+	w, errW := strconv.Atoi(strings.TrimSpace(size[0]))
+	h, errH := strconv.Atoi(strings.TrimSpace(size[1]))
+	if errW != nil || errH != nil || w < 0 || h < 0 {
+		return "", fmt.Errorf("the bounded grid %q size should be non-negative integers", s)
+	}
+	if w > maxPatternCells || h > maxPatternCells || w*h > maxPatternCells {
+		return "", fmt.Errorf("the bounded grid %q is too large", s)
+	}
+	switch {
+	case kind == "P" && w > 0 && h > 0:
+		p.Boundary = BoundaryNone
+	case kind == "T" && w > 0 && h > 0:
+		p.Boundary = BoundaryTorus
+	case kind == "T" && w > 0:
+		p.Boundary = BoundaryWrapX
+	case kind == "T" && h > 0:
+		p.Boundary = BoundaryWrapY
+	default:
+		return fmt.Sprintf("the unbounded grid %q is not supported, the plane is used", s), nil
+	}
+	p.FieldWidth, p.FieldHeight = w, h
+	return "", nil
+}
+
+//gridSuffix returns Golly's bounded grid suffix of the rule for the field of the size with the boundary
+//the wrapped axis is unbounded for Golly, so the field of the single wrapped axis is loaded there as the infinite cylinder
+func gridSuffix(b Boundary, width int, height int) string {
+	switch b {
+	case BoundaryTorus:
+		return fmt.Sprintf(":T%d,%d", width, height)
+	case BoundaryWrapX:
+		return fmt.Sprintf(":T%d,0", width)
+	case BoundaryWrapY:
+		return fmt.Sprintf(":T0,%d", height)
+	}
+	return fmt.Sprintf(":P%d,%d", width, height)
+}
+
+//max returns the larger of a and b
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+//min returns the smaller of a and b
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,199 @@
+package universe
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadRLE(t *testing.T) {
+	glider := "#N Glider\n#O Richard K. Guy\n#C The smallest spaceship\n#c found in 1969\n#R 10 20\nx = 3, y = 3, rule = B3/S23\nbob$2bo$3o!\n"
+	p, warnings, err := LoadRLE(strings.NewReader(glider))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RLE{
+		Name:        "Glider",
+		Author:      "Richard K. Guy",
+		Comments:    []string{"The smallest spaceship", "found in 1969"},
+		Rule:        "B3/S23",
+		Width:       3,
+		Height:      3,
+		X:           10,
+		Y:           20,
+		Coordinates: [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}},
+	}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("got warnings %v, want none", warnings)
+	}
+
+	tests := []struct {
+		name         string
+		rle          string
+		wantCells    int
+		wantWarnings int
+		wantErr      error
+	}{
+		{"multiple patterns", "x = 2, y = 1\n2o!\n#N second\nx = 1, y = 1\no!\n", 2, 1, nil},
+		{"multi-line with counts", "x = 12, y = 2\n12o$\n3b2o!", 14, 0, nil},
+		{"not terminated", "x = 1, y = 1\no\n", 1, 1, nil},
+		{"no header", "#N empty\n", 0, 0, ErrInvalidRLE},
+		{"bad header", "x = a, y = 1\no!\n", 0, 0, ErrInvalidRLE},
+		{"bad char", "x = 1, y = 1\no*!\n", 0, 0, ErrInvalidRLE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, warnings, err := LoadRLE(strings.NewReader(tt.rle))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(p.Coordinates) != tt.wantCells || len(warnings) != tt.wantWarnings {
+				t.Errorf("got %d cells and warnings %v, want %d cells and %d warnings", len(p.Coordinates), warnings, tt.wantCells, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestSaveRLE(t *testing.T) {
+	a := createArea(200, 10)
+	glider := [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}
+	for _, c := range glider {
+		a.Entities[c[1]+5][c[0]+10] = true
+	}
+	//the long row checks the line wrapping and the empty row the merged line ends
+	for x := 0; x < 200; x += 2 {
+		a.Entities[9][x] = true
+	}
+	b := strings.Builder{}
+	if err := SaveRLE(&b, a, ConwayRule, BoundaryNone); err != nil {
+		t.Fatal(err)
+	}
+	p, warnings, err := LoadRLE(strings.NewReader(b.String()))
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("got error %v and warnings %v for %q", err, warnings, b.String())
+	}
+	if p.Width != 199 || p.Height != 5 || p.Rule != ConwayRule.String() {
+		t.Errorf("got %dx%d with rule %q, want 199x5 with rule %q", p.Width, p.Height, p.Rule, ConwayRule.String())
+	}
+	got := createArea(200, 10)
+	for _, c := range p.Coordinates {
+		got.Entities[c[1]+5][c[0]] = true
+	}
+	if d, _ := Diff(a, got); len(d) != 0 {
+		t.Errorf("the loaded pattern differs in %v cells:\n%s", len(d), b.String())
+	}
+}
+
+func TestRLE_BoundedGrid(t *testing.T) {
+	//the wrapped axis of the cylinder is the only bounded one
+	sizes := map[Boundary][2]int{BoundaryNone: {64, 48}, BoundaryWrapX: {64, 0}, BoundaryWrapY: {0, 48}, BoundaryTorus: {64, 48}}
+	for _, b := range Boundaries {
+		t.Run(string(b), func(t *testing.T) {
+			a := newTestArea(64, 48, [][]int{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+			buf := strings.Builder{}
+			if err := SaveRLE(&buf, a, ConwayRule, b); err != nil {
+				t.Fatal(err)
+			}
+			p, warnings, err := LoadRLE(strings.NewReader(buf.String()))
+			if err != nil || len(warnings) != 0 {
+				t.Fatalf("got error %v and warnings %v for %q", err, warnings, buf.String())
+			}
+			if p.Rule != ConwayRule.String() || p.Boundary != b {
+				t.Errorf("got rule %q with boundary %q, want %q with %q", p.Rule, p.Boundary, ConwayRule.String(), b)
+			}
+			if want := sizes[b]; p.FieldWidth != want[0] || p.FieldHeight != want[1] {
+				t.Errorf("got field %dx%d, want %dx%d", p.FieldWidth, p.FieldHeight, want[0], want[1])
+			}
+		})
+	}
+
+	tests := []struct {
+		name         string
+		rle          string
+		wantWarnings int
+		wantErr      error
+	}{
+		{"no grid", "x = 1, y = 1, rule = B3/S23\no!", 0, nil},
+		{"klein bottle", "x = 1, y = 1, rule = B3/S23:K10,10\no!", 1, nil},
+		{"shifted torus", "x = 1, y = 1, rule = B3/S23:T10+1,10\no!", 1, nil},
+		{"unbounded", "x = 1, y = 1, rule = B3/S23:P0,0\no!", 1, nil},
+		{"no height", "x = 1, y = 1, rule = B3/S23:T10\no!", 0, ErrInvalidRLE},
+		{"bad size", "x = 1, y = 1, rule = B3/S23:Tx,10\no!", 0, ErrInvalidRLE},
+		{"too large", "x = 1, y = 1, rule = B3/S23:T100000,100000\no!", 0, ErrInvalidRLE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, warnings, err := LoadRLE(strings.NewReader(tt.rle))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (len(warnings) != tt.wantWarnings || p.Rule != "B3/S23") {
+				t.Errorf("got rule %q and warnings %v, want B3/S23 and %d warnings", p.Rule, warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestSaveRLE_WideSparse(t *testing.T) {
+	a := createArea(100000, 4)
+	cells := [][]int{{0, 0}, {99999, 0}, {50000, 2}, {3, 3}}
+	for _, c := range cells {
+		a.Entities[c[1]][c[0]] = true
+	}
+	b := strings.Builder{}
+	if err := SaveRLE(&b, a, ConwayRule, BoundaryNone); err != nil {
+		t.Fatal(err)
+	}
+	//the strict parsers accept the pattern lines up to 70 characters
+	for i, line := range strings.Split(strings.TrimSpace(b.String()), "\n")[1:] {
+		if len(line) > rleLineLength {
+			t.Errorf("got %d characters in the pattern line %d, want at most %d", len(line), i+1, rleLineLength)
+		}
+	}
+	p, warnings, err := LoadRLE(strings.NewReader(b.String()))
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("got error %v and warnings %v for %q", err, warnings, b.String())
+	}
+	if !reflect.DeepEqual(p.Coordinates, cells) {
+		t.Errorf("got %v, want %v", p.Coordinates, cells)
+	}
+}
+
+func TestLoadRLE_SplitRuns(t *testing.T) {
+	//the run counts are split from their tags by the line breaks
+	p, _, err := LoadRLE(strings.NewReader("x = 16, y = 4\n1\n5bo$\n2\n$2\no!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]int{{15, 0}, {0, 3}, {1, 3}}; !reflect.DeepEqual(p.Coordinates, want) {
+		t.Errorf("got %v, want %v", p.Coordinates, want)
+	}
+	if _, _, err := LoadRLE(strings.NewReader("x = 1, y = 1\n99999999999999999999o!")); !errors.Is(err, ErrInvalidRLE) {
+		t.Errorf("got error %v for the huge run count, want %v", err, ErrInvalidRLE)
+	}
+}
+
+func TestLoadRLE_Size(t *testing.T) {
+	tests := []struct {
+		name string
+		rle  string
+	}{
+		{"the run beyond the width", "x = 3, y = 1\n1000000000o!"},
+		{"the row beyond the height", "x = 3, y = 1\n$o!"},
+		{"the size too large", "x = 100000, y = 100000\no!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := LoadRLE(strings.NewReader(tt.rle)); !errors.Is(err, ErrInvalidRLE) {
+				t.Errorf("got error %v, want %v", err, ErrInvalidRLE)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package universe
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Rule represents the life-like cellular automaton rule in the B/S notation, e.g. B3/S23
+type Rule struct {
+	Name    string  //human readable name of the rule, optional
+	Birth   [9]bool //the dead cell with N live neighbours becomes alive
+	Survive [9]bool //the live cell with N live neighbours stays alive
+	Colors  int     //the count of the live cells colors for the multi-color rules, 0 - the two-state rule
+}
+
+var (
+	ErrInvalidRule = errors.New("invalid rule, the rule should be in B/S notation, e.g. B3/S23")
+
+	//ConwayRule is the classic "The Life" game rule
+	ConwayRule = MustParseRule("B3/S23", "Conway")
+
+	//PresetRules is the list of well-known rules which can be switched in the UI
+	PresetRules = []Rule{
+		ConwayRule,
+		MustParseRule("B36/S23", "HighLife"),
+		MustParseRule("B3678/S34678", "Day & Night"),
+		MustParseRule("B2/S", "Seeds"),
+		MustParseRule("B3/S012345678", "Life without Death"),
+		MustParseRule("B1357/S1357", "Replicator"),
+	}
+
+	//QuadLifeRule is the four-color variant of the Conway rule, the newborn cell takes the majority color of its parents
+	//or the fourth color if all three parents differ
+	QuadLifeRule = Rule{Name: "QuadLife", Birth: [9]bool{3: true}, Survive: [9]bool{2: true, 3: true}, Colors: 4}
+)
+
+//suggestedDensities is the share of the live cells the random seeding of the preset rules starts with by default
+//the expanding rules need the sparse fields, the dense fields of Conway's rule die out in the overcrowding
+var suggestedDensities = map[string]float64{
+	"B3/S23":        0.3,
+	"B36/S23":       0.3,
+	"B3678/S34678":  0.5,
+	"B2/S":          0.05,
+	"B3/S012345678": 0.1,
+	"B1357/S1357":   0.05,
+	"QuadLife":      0.3,
+}
+
+//SuggestedDensity returns the share of the live cells the random seeding by the rule starts with by default
+//DefDensity is returned for the rules with no suggested density
+func SuggestedDensity(r Rule) float64 {
+	if d, ok := suggestedDensities[r.String()]; ok {
+		return d
+	}
+	return DefDensity
+}
+
+//MaxColors is the maximal count of the live cells colors of the multi-color rules
+const MaxColors = 4
+
+//ParseRule parses the rule in the B/S notation, e.g. B3/S23, or the name of the multi-color rule, e.g. quadlife
+//the parts may be swapped (S23/B3), the digits may be in any order and repeated, the case and the spaces are ignored
+//the invalid rules are rejected by ErrInvalidRule with the description of the problem
+func ParseRule(s string) (Rule, error) {
+	r := Rule{}
+	if strings.EqualFold(strings.TrimSpace(s), QuadLifeRule.Name) {
+		return QuadLifeRule, nil
+	}
+	parts := strings.Split(strings.ToUpper(strings.Join(strings.Fields(s), "")), "/")
+	if len(parts) != 2 {
+		return r, fmt.Errorf("%w: %q should have the B and S parts separated by /", ErrInvalidRule, s)
+	}
+	if strings.HasPrefix(parts[0], "S") && strings.HasPrefix(parts[1], "B") {
+		parts[0], parts[1] = parts[1], parts[0]
+	}
+	if !strings.HasPrefix(parts[0], "B") || !strings.HasPrefix(parts[1], "S") {
+		return r, fmt.Errorf("%w: %q should have one part prefixed by B and the other by S", ErrInvalidRule, s)
+	}
+	if err := parseNeighbours(parts[0][1:], &r.Birth); err != nil {
+		return r, err
+	}
+	if err := parseNeighbours(parts[1][1:], &r.Survive); err != nil {
+		return r, err
+	}
+	return r, nil
+}
+
+//MustParseRule parses the rule and sets its name, panics if the rule is invalid
+func MustParseRule(s string, name string) Rule {
+	r, err := ParseRule(s)
+	if err != nil {
+		panic(err)
+	}
+	r.Name = name
+	return r
+}
+
+//String returns the rule in the B/S notation, the multi-color rules are returned by the name
+func (r Rule) String() string {
+	if r.Colors > 0 {
+		return r.Name
+	}
+	var b strings.Builder
+	b.WriteString("B")
+	for n, on := range r.Birth {
+		if on {
+			b.WriteByte(byte('0' + n))
+		}
+	}
+	b.WriteString("/S")
+	for n, on := range r.Survive {
+		if on {
+			b.WriteByte(byte('0' + n))
+		}
+	}
+	return b.String()
+}
+
+//nextState applies the rule to the cell with liveNeighbours
+func (r *Rule) nextState(live bool, liveNeighbours int) bool {
+	if live {
+		return r.Survive[liveNeighbours]
+	}
+	return r.Birth[liveNeighbours]
+}
+
+//parseNeighbours sets the flags for the neighbours counts listed in digits
+func parseNeighbours(digits string, counts *[9]bool) error {
+	for _, d := range digits {
+		if d < '0' || d > '8' {
+			return fmt.Errorf("%w: %q is not the neighbours count 0-8", ErrInvalidRule, d)
+		}
+		counts[d-'0'] = true
+	}
+	return nil
+}
+
+//newbornColor returns the color of the cell born from the parents with the counts of each color
+//the majority color is taken, if all the parents differ the first unused color is taken (QuadLife)
+//the ties are broken by the lowest color, so the runs are reproducible
+func newbornColor(counts *[MaxColors + 1]int, colors int) uint8 {
+	best := uint8(1)
+	for c := 2; c <= colors; c++ {
+		if counts[c] > counts[best] {
+			best = uint8(c)
+		}
+	}
+	if counts[best] > 1 {
+		return best
+	}
+	for c := 1; c <= colors; c++ {
+		if counts[c] == 0 {
+			return uint8(c)
+		}
+	}
+	return best
+}
@@ -0,0 +1,127 @@
+package universe
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{"B3/S23", "B3/S23", nil},
+		{"b36/s23", "B36/S23", nil},
+		{" B2/S ", "B2/S", nil},
+		{"B/S", "B/S", nil},
+		{"QuadLife", "QuadLife", nil},
+		{"B3/S32", "B3/S23", nil},
+		{"S23/B3", "B3/S23", nil},
+		{"s32/b63", "B36/S23", nil},
+		{"B 3 / S 2 3", "B3/S23", nil},
+		{"B33/S223", "B3/S23", nil},
+		{"B3/S2x", "", ErrInvalidRule},
+		{"B3/B2", "", ErrInvalidRule},
+		{"B3/S23/C4", "", ErrInvalidRule},
+		{"B9/S23", "", ErrInvalidRule},
+		{"B3S23", "", ErrInvalidRule},
+		{"23/3", "", ErrInvalidRule},
+		{"", "", ErrInvalidRule},
+	}
+	for _, tt := range tests {
+		r, err := ParseRule(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseRule(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && r.String() != tt.want {
+			t.Errorf("ParseRule(%q) = %v, want %v", tt.in, r, tt.want)
+		}
+	}
+}
+
+func TestSuggestedDensity(t *testing.T) {
+	tests := []struct {
+		rule string
+		want float64
+	}{
+		{"B3/S23", 0.3},
+		{"s/b2", 0.05}, //the parsed rules match the presets
+		{"quadlife", 0.3},
+		{"B34/S34", DefDensity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r, err := ParseRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := SuggestedDensity(r); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseUniverse_SetRule(t *testing.T) {
+	stateCh := newStateCh()
+	u := newBaseUniverse(newUniverseOptions(), stateCh)
+	u.Settle([][]int{{4, 5}, {5, 5}}) //dies in one step by the Conway's rule
+	seeds := MustParseRule("B2/S", "Seeds")
+	u.SetRule(seeds)
+	prev := u.Area()
+	u.Step()
+	waitMode(stateCh, RunningStateManual)
+	if d, _ := Diff(nextGeneration(prev, seeds, BoundaryNone), u.Area()); len(d) != 0 {
+		t.Errorf("the step by the new rule differs in %v", d)
+	}
+	if u.Status().LiveCells == 0 {
+		t.Error("the field is empty after the step by the new rule")
+	}
+	if r := u.Options().Advanced[AdvRule]; r != seeds.String() {
+		t.Errorf("got rule option %v, want %v", r, seeds)
+	}
+	u.Close()
+}
+
+func TestQuadLife_AllParentsDiffer(t *testing.T) {
+	for _, name := range engineNames() {
+		o := newUniverseOptions()
+		o.Advanced = map[string]interface{}{AdvRule: "quadlife"}
+		u := engines[name](o, newStateCh())
+		//the blinker with three different colors, the newborns above and below the centre have all the parents differ
+		u.Settle([][]int{{4, 5, 1}, {5, 5, 2}, {6, 5, 3}})
+		u.StepSync()
+		a := u.Area()
+		if a.Colors == nil {
+			t.Fatalf("%s: the colors are not kept by the multi-color rule", name)
+		}
+		for _, p := range []Point{{5, 4}, {5, 6}} {
+			if !a.Entities[p.Y][p.X] || a.Colors[p.Y][p.X] != 4 {
+				t.Errorf("%s: the newborn at %v is %v with color %d, want live with color 4", name, p, a.Entities[p.Y][p.X], a.Colors[p.Y][p.X])
+			}
+		}
+		if c := a.Colors[5][5]; c != 2 {
+			t.Errorf("%s: the survivor changed the color to %d, want 2", name, c)
+		}
+		u.Close()
+	}
+}
+
+func TestNewbornColor(t *testing.T) {
+	tests := []struct {
+		counts [MaxColors + 1]int
+		want   uint8
+	}{
+		{[MaxColors + 1]int{0, 2, 1, 0, 0}, 1},
+		{[MaxColors + 1]int{0, 0, 0, 3, 0}, 3},
+		{[MaxColors + 1]int{0, 1, 0, 1, 1}, 2},
+		{[MaxColors + 1]int{0, 0, 1, 1, 1}, 1},
+	}
+	for _, tt := range tests {
+		if got := newbornColor(&tt.counts, QuadLifeRule.Colors); got != tt.want {
+			t.Errorf("newbornColor(%v) = %d, want %d", tt.counts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package universe
+
+//Line returns the points of the straight line from a to b by the Bresenham's algorithm, both ends are included
+func Line(a Point, b Point) []Point {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	points := make([]Point, 0, max(dx, -dy)+1)
+	err := dx + dy
+	for p := a; ; {
+		points = append(points, p)
+		if p == b {
+			break
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			p.Y += sy
+		}
+	}
+	return points
+}
+
+//Rect returns the points of the rectangle with the opposite corners a and b, only the border if filled is not set
+func Rect(a Point, b Point, filled bool) []Point {
+	x1, x2 := min(a.X, b.X), max(a.X, b.X)
+	y1, y2 := min(a.Y, b.Y), max(a.Y, b.Y)
+	points := make([]Point, 0)
+	for y := y1; y <= y2; y++ {
+		for x := x1; x <= x2; x++ {
+			if filled || y == y1 || y == y2 || x == x1 || x == x2 {
+				points = append(points, Point{x, y})
+			}
+		}
+	}
+	return points
+}
+
+//abs returns the absolute value of v
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+//sign returns -1, 0 or 1 by the sign of v
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
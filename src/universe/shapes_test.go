@@ -0,0 +1,40 @@
+package universe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want []Point
+	}{
+		{"single", Point{2, 3}, Point{2, 3}, []Point{{2, 3}}},
+		{"horizontal", Point{0, 1}, Point{3, 1}, []Point{{0, 1}, {1, 1}, {2, 1}, {3, 1}}},
+		{"vertical up", Point{1, 2}, Point{1, 0}, []Point{{1, 2}, {1, 1}, {1, 0}}},
+		{"diagonal", Point{3, 3}, Point{0, 0}, []Point{{3, 3}, {2, 2}, {1, 1}, {0, 0}}},
+		{"shallow", Point{0, 0}, Point{4, 2}, []Point{{0, 0}, {1, 1}, {2, 1}, {3, 2}, {4, 2}}},
+	}
+	for _, tt := range tests {
+		if got := Line(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Line(%v, %v) = %v, want %v", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestRect(t *testing.T) {
+	outline := Rect(Point{3, 2}, Point{0, 0}, false)
+	if len(outline) != 10 {
+		t.Errorf("got %v outline points, want 10", len(outline))
+	}
+	for _, p := range outline {
+		if p == (Point{1, 1}) || p == (Point{2, 1}) {
+			t.Errorf("the inner point %v is in the outline", p)
+		}
+	}
+	if filled := Rect(Point{0, 0}, Point{3, 2}, true); len(filled) != 12 {
+		t.Errorf("got %v filled points, want 12", len(filled))
+	}
+}
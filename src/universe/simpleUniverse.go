@@ -3,24 +3,33 @@ package universe
 import "time"
 
 /*
-	Simple Universe implementation with two buffers
-	All cells state is calculated to the new buffer and then this buffer data is copied to the universe replacing the old one
+Simple Universe implementation with two buffers
+All cells state is calculated to the new buffer and then this buffer data is copied to the universe replacing the old one
 */
 type SimpleUniverse struct {
 	*BaseUniverse
 	tmpBuff Area
 }
 
-func NewSimpleUniverse(o *Options, stateCh chan Status) Universe {
-	su := SimpleUniverse{BaseUniverse: NewBaseUniverse(o, stateCh)}
+func NewSimpleUniverse(o *Options, stateCh chan Status) (Universe, error) {
+	bu, err := NewBaseUniverse(o, stateCh)
+	if err != nil {
+		return nil, err
+	}
+	su := SimpleUniverse{BaseUniverse: bu}
 	//redefine the nextIteration
 	su.BaseUniverse.nextIteration = su.nextIteration
+	su.BaseUniverse.memoryUsage = su.memoryUsage
 	su.tmpBuff = createArea(su.area.Width, su.area.Height)
 	su.options.Advanced["engine"] = "simple"
-	return &su
+	return &su, nil
 }
 
 func (su *SimpleUniverse) nextIteration() (hasLiveEnitities bool, changed bool) {
+	if su.rule.Colors > 0 {
+		//the buffers don't keep the colors
+		return su._nextIteration()
+	}
 	su.area.Lock()
 	defer su.area.Unlock()
 	start := time.Now()
@@ -40,8 +49,15 @@ func (su *SimpleUniverse) nextIteration() (hasLiveEnitities bool, changed bool)
 		copy(su.area.Entities[y], su.tmpBuff.Entities[y])
 	}
 
+	su.state.Lock()
 	su.state.LiveCells = liveCells
 	su.state.IterationTime = time.Since(start)
+	su.state.Unlock()
 	hasLiveEnitities = liveCells > 0
 	return
 }
+
+//memoryUsage estimates the bytes of the area and the buffer
+func (su *SimpleUniverse) memoryUsage() int {
+	return areaBytes(su.area.Area) + areaBytes(su.tmpBuff)
+}
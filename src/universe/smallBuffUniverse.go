@@ -7,6 +7,7 @@ import "time"
 	nextIteration uses small buffer to store the current and previous lines only.
     the first line of this buffer is copied to the main buffer as calculating moves to the next line
 	also here we have small optimization to reduce memory copying
+	the new first line is kept in the third buffer line until the end on the vertically wrapped field, the last line reads the old one
 */
 
 type SmallBuffUniverse struct {
@@ -14,16 +15,25 @@ type SmallBuffUniverse struct {
 	tmpBuff Area
 }
 
-func NewSmallBuffUniverse(o *Options, stateCh chan Status) Universe {
-	su := SmallBuffUniverse{BaseUniverse: NewBaseUniverse(o, stateCh)}
+func NewSmallBuffUniverse(o *Options, stateCh chan Status) (Universe, error) {
+	bu, err := NewBaseUniverse(o, stateCh)
+	if err != nil {
+		return nil, err
+	}
+	su := SmallBuffUniverse{BaseUniverse: bu}
 	//redefine the nextIteration
 	su.BaseUniverse.nextIteration = su.nextIteration
-	su.tmpBuff = createArea(su.area.Width, 2)
+	su.BaseUniverse.memoryUsage = su.memoryUsage
+	su.tmpBuff = createArea(su.area.Width, 3)
 	su.options.Advanced["engine"] = "smallBuff"
-	return &su
+	return &su, nil
 }
 
 func (su *SmallBuffUniverse) nextIteration() (hasLiveEnitities bool, changed bool) {
+	if su.rule.Colors > 0 {
+		//the buffers don't keep the colors
+		return su._nextIteration()
+	}
 	su.area.Lock()
 	defer su.area.Unlock()
 	start := time.Now()
@@ -37,14 +47,26 @@ func (su *SmallBuffUniverse) nextIteration() (hasLiveEnitities bool, changed boo
 			changed = changed || nextState != bool(su.area.Entities[y][x])
 			su.tmpBuff.Entities[1][x] = Cell(nextState)
 		}
-		if y-1 >= 0 {
+		if y-1 > 0 || y-1 == 0 && !su.wrapY {
 			copy(su.area.Entities[y-1], su.tmpBuff.Entities[0])
+		} else if y-1 == 0 {
+			copy(su.tmpBuff.Entities[2], su.tmpBuff.Entities[0])
 		}
 		su.tmpBuff.Entities[0], su.tmpBuff.Entities[1] = su.tmpBuff.Entities[1], su.tmpBuff.Entities[0]
 	}
 	copy(su.area.Entities[su.area.Height-1], su.tmpBuff.Entities[0])
+	if su.wrapY && su.area.Height > 1 {
+		copy(su.area.Entities[0], su.tmpBuff.Entities[2])
+	}
+	su.state.Lock()
 	su.state.LiveCells = liveCells
 	su.state.IterationTime = time.Since(start)
+	su.state.Unlock()
 	hasLiveEnitities = liveCells > 0
 	return
 }
+
+//memoryUsage estimates the bytes of the area and the three rows buffer
+func (su *SmallBuffUniverse) memoryUsage() int {
+	return areaBytes(su.area.Area) + areaBytes(su.tmpBuff)
+}
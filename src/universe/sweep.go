@@ -0,0 +1,139 @@
+package universe
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+//DefDensity is the share of the live cells in the random area
+const DefDensity = 0.5
+
+//SweepResult is the outcome of the simulation started from the random seed
+type SweepResult struct {
+	Seed            int64
+	Generations     int //the generation the final state is reached at (the cycle starts at), the steps limit if it is not reached
+	Period          int //the period of the final state: 1 - still life, 0 - extinct or not stabilized
+	FinalPopulation int
+	PeakPopulation  int
+	PeakGeneration  int
+	FinishReason    FinishReason
+	Encoding        string //the initial area encoded by EncodeArea, reloadable without the seed
+}
+
+//the orders of the sweep results
+const (
+	SweepSortGenerations = "generations" //the longest living first, then the highest peak
+	SweepSortPeak        = "peak"        //the highest peak population first, then the longest living
+	SweepSortFinal       = "final"       //the largest final population first, then the longest living
+)
+
+//SweepSorts is the list of the sweep results orders, the first is the default
+var SweepSorts = []string{SweepSortGenerations, SweepSortPeak, SweepSortFinal}
+
+//RandomArea creates the area with the live cells placed by the seeded random generator
+//density is the share of the live cells
+func RandomArea(width int, height int, density float64, seed int64) Area {
+	a := createArea(width, height)
+	r := rand.New(rand.NewSource(seed))
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			a.Entities[y][x] = Cell(r.Float64() < density)
+		}
+	}
+	return a
+}
+
+//SweepSeeds simulates the random areas with the seeds 1..seeds up to steps generations each
+//the dimensions, the rule and the boundary are taken from the options, the density from the AdvDensity option (SuggestedDensity of the rule if unset)
+//the simulations run in parallel, the results are sorted by SweepSortGenerations
+func SweepSeeds(o *Options, seeds int, steps int) []SweepResult {
+	if o == nil {
+		o = &DefaultUniverseOptions
+	}
+	//the universe is used to read the advanced options only
+	u := BaseUniverse{options: *o}
+	rule := ConwayRule
+	if r, err := ParseRule(u.advancedString(AdvRule, "")); err == nil {
+		rule = r
+	}
+	boundary, _ := ParseBoundary(u.advancedString(AdvBoundary, ""))
+	density, ok := o.Advanced[AdvDensity].(float64)
+	if !ok {
+		density = SuggestedDensity(rule)
+	}
+
+	results := make([]SweepResult, seeds)
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				a := RandomArea(o.Width, o.Height, density, int64(i+1))
+				results[i] = sweep(a, rule, boundary, steps)
+				results[i].Seed = int64(i + 1)
+			}
+		}()
+	}
+	for i := 0; i < seeds; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	SortSweepResults(results, SweepSortGenerations)
+	return results
+}
+
+//SortSweepResults sorts the results by one of SweepSorts, the ties keep their order
+func SortSweepResults(results []SweepResult, by string) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		switch by {
+		case SweepSortPeak:
+			if a.PeakPopulation != b.PeakPopulation {
+				return a.PeakPopulation > b.PeakPopulation
+			}
+			return a.Generations > b.Generations
+		case SweepSortFinal:
+			if a.FinalPopulation != b.FinalPopulation {
+				return a.FinalPopulation > b.FinalPopulation
+			}
+			return a.Generations > b.Generations
+		}
+		if a.Generations != b.Generations {
+			return a.Generations > b.Generations
+		}
+		return a.PeakPopulation > b.PeakPopulation
+	})
+}
+
+//sweep simulates the area up to steps generations or until it dies out or repeats, the cycles are detected by AnalyzeLifespan
+func sweep(a Area, r Rule, b Boundary, steps int) (res SweepResult) {
+	res.Encoding = EncodeArea(a)
+	if steps <= 0 {
+		res.PeakPopulation = population(a)
+		res.FinalPopulation, res.FinishReason = res.PeakPopulation, FinishReasonMaxSteps
+		return
+	}
+	l := AnalyzeLifespan(a, r, b, steps)
+	res.Generations, res.Period = l.Generations, l.Period
+	res.FinalPopulation, res.PeakPopulation, res.PeakGeneration = l.FinalPopulation, l.PeakPopulation, l.PeakGeneration
+	res.FinishReason = l.FinishReason
+	return
+}
+
+//population returns the count of the live cells in the area
+func population(a Area) (p int) {
+	for y := range a.Entities {
+		for x := range a.Entities[y] {
+			if a.Entities[y][x] {
+				p++
+			}
+		}
+	}
+	return
+}
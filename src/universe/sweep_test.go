@@ -0,0 +1,92 @@
+package universe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRandomArea(t *testing.T) {
+	a := RandomArea(20, 10, 0.3, 7)
+	if !reflect.DeepEqual(a, RandomArea(20, 10, 0.3, 7)) {
+		t.Error("the areas of the same seed differ")
+	}
+	if p := population(RandomArea(20, 10, 0, 7)); p != 0 {
+		t.Errorf("got %d live cells with zero density, want 0", p)
+	}
+}
+
+func TestSweepSeeds(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 16, 16
+	results := SweepSeeds(o, 20, 50)
+	if len(results) != 20 {
+		t.Fatalf("got %d results, want 20", len(results))
+	}
+	seen := map[int64]bool{}
+	for i, r := range results {
+		seen[r.Seed] = true
+		if i > 0 && r.Generations > results[i-1].Generations {
+			t.Errorf("the results are not sorted at %d", i)
+		}
+		//the sweep is reproducible by the seed, the unset density is suggested by the rule
+		want := sweep(RandomArea(16, 16, SuggestedDensity(ConwayRule), r.Seed), ConwayRule, BoundaryNone, 50)
+		want.Seed = r.Seed
+		if r != want {
+			t.Errorf("got %+v, want %+v", r, want)
+		}
+	}
+	if len(seen) != 20 {
+		t.Errorf("got %d distinct seeds, want 20", len(seen))
+	}
+}
+
+func TestSweep_Cycle(t *testing.T) {
+	//the blinker repeats from the start
+	blinker := newTestArea(5, 5, [][]int{{1, 2}, {2, 2}, {3, 2}})
+	res := sweep(blinker, ConwayRule, BoundaryNone, 50)
+	if res.FinishReason != FinishReasonCycle || res.Period != 2 || res.Generations != 0 || res.FinalPopulation != 3 {
+		t.Errorf("got %+v, want the cycle of 2 from generation 0", res)
+	}
+}
+
+func TestSortSweepResults(t *testing.T) {
+	results := []SweepResult{
+		{Seed: 1, Generations: 10, PeakPopulation: 5, FinalPopulation: 1},
+		{Seed: 2, Generations: 30, PeakPopulation: 4, FinalPopulation: 0},
+		{Seed: 3, Generations: 20, PeakPopulation: 9, FinalPopulation: 7},
+		{Seed: 4, Generations: 10, PeakPopulation: 9, FinalPopulation: 1},
+	}
+	tests := []struct {
+		by    string
+		seeds []int64
+	}{
+		{SweepSortGenerations, []int64{2, 3, 4, 1}},
+		{SweepSortPeak, []int64{3, 4, 1, 2}},
+		{SweepSortFinal, []int64{3, 1, 4, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			sorted := append([]SweepResult(nil), results...)
+			SortSweepResults(sorted, tt.by)
+			for i, r := range sorted {
+				if r.Seed != tt.seeds[i] {
+					t.Fatalf("got seed %d at %d, want the order %v", r.Seed, i, tt.seeds)
+				}
+			}
+		})
+	}
+}
+
+func TestSweepSeeds_Encoding(t *testing.T) {
+	o := newUniverseOptions()
+	o.Width, o.Height = 12, 9
+	for _, r := range SweepSeeds(o, 3, 10) {
+		a, err := DecodeArea(r.Encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(a, RandomArea(12, 9, SuggestedDensity(ConwayRule), r.Seed)) {
+			t.Errorf("seed %d: the encoding differs from the seed area", r.Seed)
+		}
+	}
+}
@@ -1,20 +1,45 @@
 package universe
 
+import "time"
+
 //Universe represent the unified Universal interface
 type Universe interface {
 	Status() Status
 	Options() Options
+	SetOptions(o Options) error
 	Area() Area
+	SetArea(a Area) error
+	Changes() (born []Point, died []Point)
 	StateCh() chan Status
+	SubscribeMode() <-chan RunningState
 	AddTemplate(tmpl Template)
 	SettleTemplate(name string)
 	SettleWithRandomData()
 	Settle(vc [][]int)
+	SettleLayout(placements []Placement, merge bool) (warnings []string)
+	InsertPattern(tmpl Template, x int, y int, anchor Anchor) (outside bool)
 	InverseCell(x int, y int)
+	SetCell(x int, y int, live bool)
+	SetCells(points []Point, live bool)
+	InvertAll()
+	CountComponents(c Connectivity)
+	Source() Source
+	SetSource(s Source)
+	Rule() Rule
+	SetRule(r Rule)
+	Boundary() Boundary
+	SetBoundary(b Boundary) error
 	RegisterViewer(v Viewer)
 	Run()
+	FastRun()
 	Stop()
 	Step()
+	StepSync()
+	StepWithin(d time.Duration) int
+	Iterate(n int, fn func(gen int, a Area) bool)
+	History() (first int, last int, ok bool)
+	Generation(n int) (Area, bool)
+	Fork(n int) error
 	Clear()
 	Close()
 }
@@ -3,6 +3,7 @@ package universe
 import (
 	"sort"
 	"testing"
+	"time"
 )
 
 var (
@@ -10,14 +11,35 @@ var (
 
 	engines = map[string]func(o *Options, stateCh chan Status) Universe{
 		"base": func(o *Options, stateCh chan Status) Universe {
-			return NewBaseUniverse(o, stateCh)
+			return newBaseUniverse(o, stateCh)
 		},
-		"simple":        NewSimpleUniverse,
-		"smallBuff":     NewSmallBuffUniverse,
-		"multithreaded": NewMultithreadedUniverse,
+		"simple":        mustUniverse(NewSimpleUniverse),
+		"smallBuff":     mustUniverse(NewSmallBuffUniverse),
+		"multithreaded": mustUniverse(NewMultithreadedUniverse),
+		"hashlife":      mustUniverse(NewHashlifeUniverse),
 	}
 )
 
+//mustUniverse wraps the engine constructor to panic on the error, the test options are expected to be valid
+func mustUniverse(newUniverse func(o *Options, stateCh chan Status) (Universe, error)) func(o *Options, stateCh chan Status) Universe {
+	return func(o *Options, stateCh chan Status) Universe {
+		u, err := newUniverse(o, stateCh)
+		if err != nil {
+			panic(err)
+		}
+		return u
+	}
+}
+
+//newBaseUniverse creates the BaseUniverse and panics on the error
+func newBaseUniverse(o *Options, stateCh chan Status) *BaseUniverse {
+	u, err := NewBaseUniverse(o, stateCh)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
 const (
 	width  = 200
 	height = 200
@@ -109,3 +131,28 @@ func Benchmark_Universe(b *testing.B) {
 		})
 	}
 }
+
+func Benchmark_Engines(b *testing.B) {
+	o := newUniverseOptions()
+	//the hashlife engine calculates the plane by the tree, the wrapped boundaries fall back to the base steps
+	o.Advanced = map[string]interface{}{AdvBoundary: string(BoundaryNone)}
+	all := benchEngines()
+	for _, e := range engineNames() {
+		b.Run(e, func(b *testing.B) {
+			steps := 0
+			var spent time.Duration
+			for i := 0; i < b.N; i++ {
+				results, err := BenchEngines(map[string]func(o *Options, stateCh chan Status) (Universe, error){e: all[e]}, o, 1, 20)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if results[0].Differs != 0 {
+					b.Fatalf("the field differs from the serial calculation in %d cells", results[0].Differs)
+				}
+				steps += results[0].Steps
+				spent += results[0].Duration
+			}
+			b.ReportMetric(float64(steps)/spent.Seconds(), "steps/s")
+		})
+	}
+}
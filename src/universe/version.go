@@ -0,0 +1,14 @@
+package universe
+
+import "runtime/debug"
+
+//develVersion is the version of the builds without the module version, e.g. the local ones
+const develVersion = "(devel)"
+
+//Version returns the module version of the build, "(devel)" if it is unknown
+func Version() string {
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "" {
+		return bi.Main.Version
+	}
+	return develVersion
+}
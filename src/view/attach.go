@@ -0,0 +1,182 @@
+package view
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jroimartin/gocui"
+	"github.com/logrusorgru/aurora"
+	"net"
+	"simlife/src/universe"
+	"sync"
+)
+
+//attachHelp is the key bindings line of the attached UI
+const attachHelp = " R: run  S: stop  N: step  C: clear  Q: detach"
+
+//AttachedUI is the console UI watching the universe streamed by Server over the Unix socket
+//detaching leaves the simulation running
+type AttachedUI struct {
+	g       *gocui.Gui
+	conn    net.Conn
+	mu      sync.Mutex //guards enc and lastErr
+	enc     *json.Encoder
+	caps    Capabilities //the server capabilities, accessed by the gocui main loop only
+	frame   Frame        //the last received frame, accessed by the gocui main loop only
+	area    universe.Area
+	lastErr error
+}
+
+//Attach connects to the server socket at path and creates the console UI showing its frames
+func Attach(path string) (*AttachedUI, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrTerminal, err)
+	}
+	a := &AttachedUI{g: g, conn: conn, enc: json.NewEncoder(conn)}
+	g.SetManagerFunc(a.layout)
+	keys := []struct {
+		key     interface{}
+		handler func(g *gocui.Gui, v *gocui.View) error
+	}{
+		{gocui.KeyCtrlC, a.detach},
+		{'q', a.detach},
+		{'r', a.sender(CommandRun)},
+		{'s', a.sender(CommandStop)},
+		{'n', a.sender(CommandStep)},
+		{'c', a.sender(CommandClear)},
+	}
+	for _, k := range keys {
+		if err := g.SetKeybinding("", k.key, gocui.ModNone, k.handler); err != nil {
+			g.Close()
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+//Start shows the frames until the UI is detached or the server is gone
+func (a *AttachedUI) Start() {
+	go a.receive()
+	if err := a.g.MainLoop(); err != nil && err != gocui.ErrQuit {
+		a.setErr(err)
+	}
+	a.g.Close()
+	_ = a.conn.Close()
+}
+
+//Err returns the error which detached the UI, nil if it is detached by the user
+func (a *AttachedUI) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.lastErr
+}
+
+func (a *AttachedUI) setErr(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.lastErr == nil {
+		a.lastErr = err
+	}
+}
+
+//receive reads the frames until the connection is closed, the UI is closed when the server is gone
+func (a *AttachedUI) receive() {
+	dec := json.NewDecoder(bufio.NewReader(a.conn))
+	var caps Capabilities
+	if err := dec.Decode(&caps); err != nil {
+		a.g.Update(func(*gocui.Gui) error {
+			a.setErr(fmt.Errorf("the server is gone: %v", err))
+			return gocui.ErrQuit
+		})
+		return
+	}
+	a.g.Update(func(*gocui.Gui) error {
+		a.caps = caps
+		return nil
+	})
+	for {
+		var f Frame
+		if err := dec.Decode(&f); err != nil {
+			a.g.Update(func(*gocui.Gui) error {
+				a.setErr(fmt.Errorf("the server is gone: %v", err))
+				return gocui.ErrQuit
+			})
+			return
+		}
+		area, err := universe.DecodeArea(f.Field)
+		a.g.Update(func(*gocui.Gui) error {
+			a.frame = f
+			if err == nil {
+				a.area = area
+			}
+			return nil
+		})
+	}
+}
+
+//sender returns the key handler sending the command to the server
+func (a *AttachedUI) sender(command string) func(g *gocui.Gui, v *gocui.View) error {
+	return func(_ *gocui.Gui, _ *gocui.View) error {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if err := a.enc.Encode(Command{Command: command}); err != nil && a.lastErr == nil {
+			a.lastErr = err
+			return gocui.ErrQuit
+		}
+		return nil
+	}
+}
+
+//detach quits the UI, the simulation goes on
+func (a *AttachedUI) detach(_ *gocui.Gui, _ *gocui.View) error {
+	return gocui.ErrQuit
+}
+
+//layout draws the field cropped to the view and the status line of the last frame
+func (a *AttachedUI) layout(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+	v, err := g.SetView("field", 0, 0, maxX-1, maxY-3)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	v.Title = fmt.Sprintf("Attached: %v, %v engine %v", a.frame.Rule, a.caps.Engine, a.caps.Version)
+	v.Clear()
+	w, h := v.Size()
+	var b bytes.Buffer
+	live, dead := aurora.Green("█").BgBrightGreen().String(), "░"
+	for y := 0; y < min(h, a.area.Height); y++ {
+		if y != 0 {
+			b.WriteByte('\n')
+		}
+		for x := 0; x < min(w, a.area.Width); x++ {
+			if a.area.Entities[y][x] {
+				b.WriteString(live)
+			} else {
+				b.WriteString(dead)
+			}
+		}
+	}
+	_, _ = v.Write(b.Bytes())
+
+	s, err := g.SetView("attachStatus", 0, maxY-2, maxX-1, maxY)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	s.Frame = false
+	s.Clear()
+	status := fmt.Sprintf(" Step: %v  Live Cells: %v  Mode: %v", a.frame.Generation, a.frame.Population, a.frame.Mode)
+	if a.frame.Reason != "" {
+		status += fmt.Sprintf(" (%v)", a.frame.Reason)
+	}
+	_, _ = fmt.Fprintln(s, status)
+	_, _ = fmt.Fprint(s, attachHelp)
+	return nil
+}
@@ -0,0 +1,211 @@
+package view
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"simlife/src/universe"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//DefAutosaveKeep is the count of the latest autosave files kept, the older ones are removed
+const DefAutosaveKeep = 3
+
+//the autosave files are named by the save time, so the names are sorted in the save order
+const (
+	autosavePrefix     = "autosave-"
+	autosaveSuffix     = ".rle"
+	autosaveTimeFormat = "20060102-150405.000"
+)
+
+//Autosave is the viewer which periodically saves the field of the first registered universe to the rotating RLE files
+//the position of the pattern is kept by the #R line, the unchanged field is not saved again
+//the files are written by the own goroutine, so the disk doesn't slow down the simulation
+type Autosave struct {
+	mu       sync.Mutex
+	u        universe.Universe
+	dir      string
+	steps    int           //save every steps generations, 0 - not by the generations
+	every    time.Duration //save every interval, 0 - not by the time
+	keep     int
+	lastStep int
+	lastTime time.Time
+	saved    universe.Area
+	lastErr  error
+	//the field waiting to be written, only the latest one is kept while the previous one is written
+	pending chan autosaveField
+	closed  bool
+	done    chan bool
+}
+
+//autosaveField is the field with the rule and the boundary taken at the save time
+type autosaveField struct {
+	a        universe.Area
+	rule     universe.Rule
+	boundary universe.Boundary
+}
+
+//NewAutosave starts the goroutine writing the saves, Close waits for the pending one
+func NewAutosave(dir string, steps int, every time.Duration) *Autosave {
+	s := &Autosave{dir: dir, steps: steps, every: every, keep: DefAutosaveKeep, lastTime: time.Now(),
+		pending: make(chan autosaveField, 1), done: make(chan bool)}
+	go s.writer()
+	return s
+}
+
+//DefaultAutosaveDir returns the autosave directory in the user's config directory, the empty string if there is no one
+func DefaultAutosaveDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "simlife", "autosave")
+}
+
+//LatestAutosave returns the path of the latest autosave file in dir, the empty string if there is no one
+func LatestAutosave(dir string) string {
+	names := autosaveFiles(dir)
+	if len(names) == 0 {
+		return ""
+	}
+	return filepath.Join(dir, names[len(names)-1])
+}
+
+//Register starts saving the universe, the other universes are ignored
+func (s *Autosave) Register(u *universe.BaseUniverse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.u == nil {
+		s.u = u
+		s.saved = u.Area()
+	}
+}
+
+//Refresh saves the field when the generations or the time interval are passed since the last save
+func (s *Autosave) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.u == nil || s.lastErr != nil || s.closed {
+		return
+	}
+	step := s.u.Status().IterationNum
+	if step < s.lastStep {
+		//the universe is cleared or restarted
+		s.lastStep = step
+	}
+	due := s.steps > 0 && step-s.lastStep >= s.steps || s.every > 0 && time.Since(s.lastTime) >= s.every
+	if !due {
+		return
+	}
+	s.lastStep, s.lastTime = step, time.Now()
+	a := s.u.Area()
+	if d, _ := universe.Diff(s.saved, a); len(d) == 0 {
+		return
+	}
+	s.saved = a
+	f := autosaveField{a, s.u.Rule(), s.u.Boundary()}
+	for {
+		select {
+		case s.pending <- f:
+			return
+		default:
+			//the writer is busy, the waiting field is outdated
+			select {
+			case <-s.pending:
+			default:
+			}
+		}
+	}
+}
+
+func (s *Autosave) Start() {
+}
+
+//Close writes the pending save and stops the writer, the later refreshes are ignored
+func (s *Autosave) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.pending)
+	s.mu.Unlock()
+	<-s.done
+}
+
+//writer writes the pending fields until the autosave is closed, the saving stops after the error
+func (s *Autosave) writer() {
+	defer close(s.done)
+	for f := range s.pending {
+		if s.Err() != nil {
+			continue
+		}
+		if err := s.save(f); err != nil {
+			s.mu.Lock()
+			s.lastErr = err
+			s.mu.Unlock()
+		}
+	}
+}
+
+//Err returns the saving error, the autosave is stopped after the error
+func (s *Autosave) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+//save writes the field to the new autosave file and removes the old ones
+func (s *Autosave) save(f autosaveField) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	name := filepath.Join(s.dir, autosavePrefix+time.Now().Format(autosaveTimeFormat)+autosaveSuffix)
+	file, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	//the corner of the live cells bounding box, 0, 0 for the empty field
+	x, y := 0, 0
+	if lo, _, ok := universe.LiveBounds(f.a); ok {
+		x, y = lo.X, lo.Y
+	}
+	_, _ = fmt.Fprintf(w, "#N autosave\n#R %d %d\n", x, y)
+	err = universe.SaveRLE(w, f.a, f.rule, f.boundary)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	names := autosaveFiles(s.dir)
+	for i := 0; i < len(names)-s.keep; i++ {
+		_ = os.Remove(filepath.Join(s.dir, names[i]))
+	}
+	return nil
+}
+
+//autosaveFiles returns the names of the autosave files in dir in the save order
+func autosaveFiles(dir string) (names []string) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	for _, f := range files {
+		if !f.IsDir() && strings.HasPrefix(f.Name(), autosavePrefix) && strings.HasSuffix(f.Name(), autosaveSuffix) {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	return
+}
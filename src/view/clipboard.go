@@ -0,0 +1,49 @@
+package view
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"runtime"
+)
+
+var (
+	errNoClipboard = errors.New("no clipboard tool found")
+)
+
+//clipboardCommands are the clipboard tools tried in order, the text is passed to the standard input
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux":   {{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}, {"clip.exe"}},
+}
+
+//imageClipboardCommands are the clipboard tools accepting the PNG image on the standard input, tried in order
+var imageClipboardCommands = map[string][][]string{
+	"linux": {{"wl-copy", "--type", "image/png"}, {"xclip", "-selection", "clipboard", "-t", "image/png"}},
+}
+
+//copyToClipboard puts the text on the system clipboard by the first available platform tool
+//returns errNoClipboard if there is no one
+func copyToClipboard(text string) error {
+	return runClipboard(clipboardCommands[runtime.GOOS], []byte(text))
+}
+
+//copyImageToClipboard puts the PNG image on the system clipboard by the first available platform tool
+//returns errNoClipboard if there is no one
+func copyImageToClipboard(png []byte) error {
+	return runClipboard(imageClipboardCommands[runtime.GOOS], png)
+}
+
+//runClipboard passes the data to the first available command
+func runClipboard(commands [][]string, data []byte) error {
+	for _, c := range commands {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = bytes.NewReader(data)
+		return cmd.Run()
+	}
+	return errNoClipboard
+}
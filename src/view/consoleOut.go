@@ -1,7 +1,10 @@
 package view
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"simlife/src/universe"
 	"sort"
 	"time"
@@ -9,21 +12,71 @@ import (
 
 type ConsoleOut struct {
 	u         universe.Universe
+	o         Options
 	startTime time.Time
+	lastMode  universe.RunningState
+	peak      universe.Status //the status with the highest live cells count
 }
 
-func NewConsoleOut() *ConsoleOut {
-	return &ConsoleOut{}
+//Summary is the machine-readable result of the run printed when Options.Summary is set
+type Summary struct {
+	Generation     int    `json:"generation"`
+	Population     int    `json:"population"`
+	Classification string `json:"classification"`
+	Reason         string `json:"reason"`
+	PeakPopulation int    `json:"peakPopulation"`
+	PeakGeneration int    `json:"peakGeneration"`
+	RuntimeMs      int64  `json:"runtimeMs"`
+}
+
+//LifespanSummary is the machine-readable result of the lifespan analysis
+type LifespanSummary struct {
+	Generations    int    `json:"generations"`
+	Period         int    `json:"period"`
+	Population     int    `json:"population"`
+	Classification string `json:"classification"`
+	Reason         string `json:"reason"`
+	PeakPopulation int    `json:"peakPopulation"`
+	PeakGeneration int    `json:"peakGeneration"`
+}
+
+//classifications maps the finish reasons to the final pattern classes
+var classifications = map[universe.FinishReason]string{
+	universe.FinishReasonExtinct: "extinct",
+	universe.FinishReasonStable:  "still life",
+	universe.FinishReasonCycle:   "oscillator",
+}
+
+func NewConsoleOut(o *Options) *ConsoleOut {
+	if o == nil {
+		o = &DefaultOptions
+	}
+	return &ConsoleOut{o: *o}
 }
 
 func (c *ConsoleOut) Refresh() {
 	st := c.u.Status()
-	if st.RunningMode == universe.RunningStateFinished {
+	finished := st.RunningMode == universe.RunningStateFinished && c.lastMode != universe.RunningStateFinished
+	c.lastMode = st.RunningMode
+	if st.LiveCells > c.peak.LiveCells {
+		c.peak = st
+	}
+	if c.o.Summary {
+		if finished {
+			c.printSummary(st)
+		}
+		return
+	}
+	if finished {
+		if c.o.Bell {
+			bell()
+		}
 		totalTime := time.Since(c.startTime).Round(time.Millisecond)
 		resultData := map[string]interface{}{
 			"Last iteration": st.IterationNum,
 			"Total time":     totalTime,
 			"Live cells":     st.LiveCells,
+			"Reason":         st.FinishReason,
 		}
 		fmt.Println("\nFinished:")
 		c.printHashData(resultData)
@@ -37,6 +90,9 @@ func (c *ConsoleOut) Refresh() {
 
 func (c *ConsoleOut) Register(u *universe.BaseUniverse) {
 	c.u = u
+	if c.o.Summary {
+		return
+	}
 	o := c.u.Options()
 	fmt.Println("Running configuration:")
 	fmt.Printf("  Dimension: %v x %v\n", o.Width, o.Height)
@@ -47,6 +103,9 @@ func (c *ConsoleOut) Register(u *universe.BaseUniverse) {
 
 func (c *ConsoleOut) Start() {
 	c.startTime = time.Now()
+	if c.o.Summary {
+		return
+	}
 	fmt.Println("\nSimulation started...")
 }
 
@@ -60,3 +119,45 @@ func (c *ConsoleOut) printHashData(d map[string]interface{}) {
 		fmt.Printf("  %s: %v\n", propName, d[propName])
 	}
 }
+
+//printSummary prints the run Summary as JSON
+func (c *ConsoleOut) printSummary(st universe.Status) {
+	class := classification(st.FinishReason)
+	enc := json.NewEncoder(os.Stdout)
+	_ = enc.Encode(Summary{
+		Generation:     st.IterationNum,
+		Population:     st.LiveCells,
+		Classification: class,
+		Reason:         string(st.FinishReason),
+		PeakPopulation: c.peak.LiveCells,
+		PeakGeneration: c.peak.IterationNum,
+		RuntimeMs:      int64(time.Since(c.startTime) / time.Millisecond),
+	})
+}
+
+//PrintLifespan prints the lifespan analysis result as the text or as the JSON LifespanSummary
+func PrintLifespan(w io.Writer, l universe.Lifespan, asJSON bool) error {
+	class := classification(l.FinishReason)
+	if asJSON {
+		return json.NewEncoder(w).Encode(LifespanSummary{
+			Generations:    l.Generations,
+			Period:         l.Period,
+			Population:     l.FinalPopulation,
+			Classification: class,
+			Reason:         string(l.FinishReason),
+			PeakPopulation: l.PeakPopulation,
+			PeakGeneration: l.PeakGeneration,
+		})
+	}
+	_, err := fmt.Fprintf(w, "Lifespan: %v generations, %v (%v)\n  Final population: %v\n  Period: %v\n  Peak population: %v at %v\n",
+		l.Generations, class, l.FinishReason, l.FinalPopulation, l.Period, l.PeakPopulation, l.PeakGeneration)
+	return err
+}
+
+//classification returns the final pattern class of the finish reason
+func classification(r universe.FinishReason) string {
+	if class, ok := classifications[r]; ok {
+		return class
+	}
+	return "undetermined"
+}
@@ -2,14 +2,22 @@ package view
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"github.com/jroimartin/gocui"
 	"github.com/logrusorgru/aurora"
+	"github.com/nsf/termbox-go"
+	"io"
+	"io/ioutil"
 	"log"
 	"simlife/src/universe"
 	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"text/tabwriter"
 	"time"
+	"unicode/utf8"
 )
 
 type keyBindings struct {
@@ -20,14 +28,139 @@ type keyBindings struct {
 	viewName string
 }
 
+//panel is the battlefield panel showing the universe
+type panel struct {
+	u        universe.Universe
+	name     string //the gocui view name
+	lastMode universe.RunningState
+	//the iteration drawn by the last frame
+	renderedIteration int
+	//the crossed wrapped edges are flashed until crossedUntil
+	crossed      edges
+	crossedUntil time.Time
+	//the field cell shown at the top left corner of the battlefield, the displayed rows are counted from the top
+	offsetX int
+	offsetY int
+	//the window is shifted from the crop anchor by the pan, the shift is wrapped around the wrapped axes
+	panX int
+	panY int
+	//the field dimensions and the wrapped axes of the last drawing
+	width  int
+	height int
+	wrapX  bool
+	wrapY  bool
+	//the view columns per field cell of the last drawing
+	cellW int
+	//the battlefield size of the last layout
+	fieldW int
+	fieldH int
+	//the time of the last field drawing, accessed by the gocui main loop only
+	renderTime time.Duration
+	//the field larger than MaxRenderCells is redrawn at most once in largeFieldInterval
+	//the skipped frame is drawn by the pending refresh, accessed by the gocui main loop only
+	nextRender    time.Time
+	renderPending bool
+	largeNotified bool //the large field notice is shown
+	//the lifespan analysis of the field, nil until it is started, accessed by the gocui main loop only
+	lifespan *lifespanAnalysis
+	//the kept past generation shown instead of the live field while scrubbing, the simulation goes on meanwhile
+	scrubbing bool
+	scrubGen  int
+}
+
+//lifespanAnalysis is the lifespan of the field taken at the step
+type lifespanAnalysis struct {
+	step int
+	done bool //the analysis is finished, l is set
+	l    universe.Lifespan
+}
+
 type ConsoleUI struct {
-	u          universe.Universe
-	g          *gocui.Gui
-	k          []keyBindings
-	liveFiller string
-	deadFiller string
+	u        universe.Universe //the universe of the focused panel
+	panels   []*panel
+	focus    int
+	lockstep bool //the simulation commands are applied to all the universes
+	//the universes run in lockstep are stepped by the shared ticker until tickerStop is closed
+	tickerStop    chan bool
+	sharedRun     int32 //the shared ticker is running, accessed atomically
+	o             Options
+	g             *gocui.Gui
+	k             []keyBindings
+	liveFiller    string
+	deadFiller    string
+	bornFiller    string
+	diedFiller    string
+	seamFiller    string
+	seamFlash     string
+	extraFiller   string
+	missingFiller string
+	ghostFiller   string
+	//the live cells fillers of the multi-color rules indexed by the color, see StateGlyphs
+	colorFillers [universe.MaxColors + 1]string
+	//the header colors
+	headerFg gocui.Attribute
+	headerBg gocui.Attribute
+	//the crop indicator variants
+	cropMessage string
+	cropGlyph   string
+	//the cells born and died on the last step are highlighted
+	changeFlash bool
+	//the wrapped edges are marked, crossed ones are flashed
+	seamIndicator bool
+	//the cells are drawn by two glyphs to look square, the visible field is twice narrower
+	squareCells bool
+	//the live cells across the wrapped edges are shown faintly on the dead edge cells
+	wrapGhosts bool
+	//the corner of the generation label of the copied image, no label if empty
+	imageLabel string
+	//the field is compared with the reference area when compare is set
+	compare   bool
+	reference universe.Area
+	//the cells born and died on the next step are shown on the paused field when preview is set
+	preview bool
+	//the cell at the cursor is explained in the status panel when inspect is set
+	inspect bool
+	cursorX int
+	cursorY int
+	//the viewport loaded from the preferences, it is restored for the first registered universe
+	savedViewport *Viewport
+	//the mouse click paints the cells around, the single cell is inverted when brushSize is 0
+	brushSize   int
+	brushCircle bool
+	//the left click always draws the live cells and the right click erases when drawMode is set, the left click inverts otherwise
+	drawMode bool
+	//the shape tool draws from the first clicked cell to the second one instead of the brush
+	tool       drawTool
+	shapeStart *universe.Point //the first clicked cell, nil until it's clicked
+	//the run key runs the simulation for holdRunWindow after the last press when holdToRun is set
+	//the key auto-repeat extends the window, so the simulation runs while the key is held
+	holdToRun bool
+	holdUntil time.Time
+	holdTimer *time.Timer //checks the window expiration, nil if the hold run is not active
+	//the last step and render timings are shown in the timings panel when timings is set
+	timings bool
+	//the cells are counted by their live neighbours for the neighbours panel when neighbours is set
+	neighbours bool
+	//the steps per second of the status panel
+	sps spsMeter
+	//the centroid velocity of the status panel
+	velocity velocityMeter
+	//the population sparkline of the status panel
+	populations populationRing
+	//the key bindings error, the failed keys are not bound
+	bindErr error
+	//the notice replaces the header message until noticeUntil
+	notice      string
+	noticeUntil time.Time
+	dirty       int32     //the display is outdated, accessed atomically
+	done        chan bool //stops the render ticker
 }
 
+var (
+	ErrKeyBindings = errors.New("key bindings failed")
+	ErrTerminal    = errors.New("can't initialize the terminal")
+)
+
 var (
 	runningStateDescr = map[universe.RunningState]string{
 		universe.RunningStateManual:   aurora.Colorize("waiting", aurora.BlueFg).String(),
@@ -35,23 +168,120 @@ var (
 		universe.RunningStateRun:      aurora.Colorize("running", aurora.CyanFg).String(),
 		universe.RunningStateFinished: aurora.Colorize("finished", aurora.RedFg).String(),
 	}
+	fastRunDescr   = aurora.Colorize("running (press Esc to stop)", aurora.CyanFg).String()
+	sharedRunDescr = aurora.Colorize("running in lockstep", aurora.CyanFg).String()
+)
+
+const (
+	headerFlashDuration = time.Millisecond * 300
+	changeFlashDuration = time.Millisecond * 200
+	seamFlashDuration   = time.Millisecond * 500
+	noticeDuration      = time.Second * 3
+	bindingsNotice      = "Some keys are not bound"
+	largeFieldNotice    = "Large field, redrawn once a second"
+	largeFieldInterval  = time.Second
+	holdRunWindow       = time.Millisecond * 600 //longer than the usual key auto-repeat delay
+	maxBrushSize        = 20
+	timingsWidth        = 26
+	neighboursWidth     = 32
+	imageCellSize       = 8    //the pixels per cell of the copied image
+	maxImageSize        = 4096 //the maximal width and height of the copied image
+	sourcePathWidth     = 20   //the source file path is shortened to fit the configuration panel
+	//the shorter intervals are lost in the sleep granularity and the step evaluation, they are shown as the max speed
+	minVisibleInterval = time.Microsecond * 100
+	maxSpeedDescr      = "max speed"
+)
+
+//drawTool is the shape drawn by the mouse clicks
+type drawTool int
+
+const (
+	toolBrush drawTool = iota
+	toolLine
+	toolRect
+	toolFilledRect
+)
+
+//toolNames are the tools descriptions in the switching order
+var toolNames = []string{"brush", "line", "rectangle", "filled rectangle"}
+
+//edges is the set of the field edges
+type edges int
+
+const (
+	edgeLeft edges = 1 << iota
+	edgeRight
+	edgeTop
+	edgeBottom
 )
 
-func NewConsoleUI() *ConsoleUI {
+//fieldOverlay is the additional data rendered over the field cells
+type fieldOverlay struct {
+	changes   map[universe.Point]bool //born cells are mapped to true, died ones to false
+	seams     edges                   //the wrapped edges
+	crossed   edges                   //the edges recently crossed by the cells
+	ghosts    edges                   //the wrapped edges the cells across are shown on
+	reference universe.Area           //the area the field is compared with, no comparison if Entities is nil
+}
+
+//NewConsoleUI creates the console UI on the terminal
+//returns ErrTerminal if the terminal can't be initialized (e.g. the output is not a TTY)
+func NewConsoleUI(o *Options) (*ConsoleUI, error) {
 
 	var err error
+	if o == nil {
+		o = &DefaultOptions
+	}
 	t := ConsoleUI{
-		liveFiller: aurora.Green("█").BgBrightGreen().String(),
-		deadFiller: "░",
+		o:             *o,
+		done:          make(chan bool),
+		liveFiller:    aurora.Green("█").BgBrightGreen().String(),
+		deadFiller:    "░",
+		bornFiller:    aurora.BrightCyan("█").BgBrightCyan().String(),
+		diedFiller:    aurora.Red("█").BgRed().String(),
+		seamFiller:    aurora.Cyan("░").String(),
+		seamFlash:     aurora.BrightYellow("░").BgYellow().String(),
+		extraFiller:   aurora.Magenta("█").BgMagenta().String(),
+		missingFiller: aurora.Yellow("▒").String(),
+		ghostFiller:   aurora.Green("░").Faint().String(),
+		colorFillers:  stateFillers(o.StateGlyphs),
+		changeFlash:   o.ChangeFlash,
+		seamIndicator: o.SeamIndicator,
+		imageLabel:    o.ImageLabel,
+		lockstep:      true,
+	}
+
+	t.cropMessage, t.cropGlyph = cropIndicator(&t.o)
+	t.headerFg, t.headerBg = headerColors(&t.o)
+	if t.o.StatusMetrics == nil {
+		t.o.StatusMetrics = DefStatusMetrics
+	}
+	if t.o.Connectivity == 0 {
+		t.o.Connectivity = universe.Connectivity8
+	}
+	if o.PrefsFile != "" {
+		if p, ok := loadPrefs(o.PrefsFile); ok {
+			t.applyPrefs(p)
+		}
 	}
 
 	t.g, err = gocui.NewGui(gocui.OutputNormal)
 	if err != nil {
-		log.Panicln(err)
+		return nil, fmt.Errorf("%w: %v", ErrTerminal, err)
 	}
 
 	t.g.Mouse = true
-	t.k = []keyBindings{
+	t.k = t.defaultKeys()
+	t.g.SetManagerFunc(t.layout)
+
+	t.bindErr = t.initKeyBindings(t.k)
+
+	return &t, nil
+}
+
+//defaultKeys returns the key bindings of the UI, the bindings with the empty name are listed with the previous one
+func (t *ConsoleUI) defaultKeys() []keyBindings {
+	keys := []keyBindings{
 		{gocui.KeyCtrlC,
 			"^C",
 			"Exit",
@@ -67,11 +297,26 @@ func NewConsoleUI() *ConsoleUI {
 			"Run",
 			t.cmdRun,
 			""},
+		{'y',
+			"Y",
+			"Hold to run",
+			t.cmdToggleHoldToRun,
+			""},
+		{'f',
+			"F",
+			"Fast run",
+			t.cmdFastRun,
+			""},
 		{'s',
 			"S",
 			"Stop",
 			t.cmdStop,
 			""},
+		{gocui.KeyEsc,
+			"Esc",
+			"Abort fast run",
+			t.cmdStop,
+			""},
 		{'c',
 			"C",
 			"Clear",
@@ -82,123 +327,990 @@ func NewConsoleUI() *ConsoleUI {
 			"Settle with random",
 			t.cmdSettleWithRandom,
 			""},
+		{'z',
+			"Z",
+			"Invert field",
+			t.cmdInvertAll,
+			""},
+		{'k',
+			"K",
+			"Count clusters",
+			t.cmdCountComponents,
+			""},
+		{'h',
+			"H",
+			"Highlight changes",
+			t.cmdToggleChangeFlash,
+			""},
+		{'u',
+			"U",
+			"Next rule",
+			t.cmdNextRule,
+			""},
+		{'t',
+			"T",
+			"Next boundary",
+			t.cmdNextBoundary,
+			""},
+		{'i',
+			"I",
+			"Seam indicator",
+			t.cmdToggleSeamIndicator,
+			""},
+		{'q',
+			"Q",
+			"Square cells",
+			t.cmdToggleSquareCells,
+			""},
+		{'g',
+			"G",
+			"Wrap ghosts",
+			t.cmdToggleWrapGhosts,
+			""},
+		{'o',
+			"O",
+			"Compare with reference",
+			t.cmdToggleCompare,
+			""},
+		{'p',
+			"P",
+			"Pin reference",
+			t.cmdPinReference,
+			""},
+		{'N',
+			"⇧N",
+			"Preview next step",
+			t.cmdTogglePreview,
+			""},
+		{'[',
+			"[]",
+			"Scrub the past generations",
+			t.cmdScrubBack,
+			""},
+		{']',
+			"",
+			"",
+			t.cmdScrubForward,
+			""},
+		{'F',
+			"⇧F",
+			"Fork the scrubbed generation",
+			t.cmdFork,
+			""},
+		{'e',
+			"E",
+			"Explain cell",
+			t.cmdToggleInspect,
+			""},
+		{gocui.KeyArrowUp,
+			"↑↓←→",
+			"Move cursor",
+			t.cmdCursorMover(0, -1),
+			""},
+		{gocui.KeyArrowDown,
+			"",
+			"",
+			t.cmdCursorMover(0, 1),
+			""},
+		{gocui.KeyArrowLeft,
+			"",
+			"",
+			t.cmdCursorMover(-1, 0),
+			""},
+		{gocui.KeyArrowRight,
+			"",
+			"",
+			t.cmdCursorMover(1, 0),
+			""},
+		{'H',
+			"⇧HJKL",
+			"Pan",
+			t.cmdPanner(-1, 0),
+			""},
+		{'J',
+			"",
+			"",
+			t.cmdPanner(0, 1),
+			""},
+		{'K',
+			"",
+			"",
+			t.cmdPanner(0, -1),
+			""},
+		{'L',
+			"",
+			"",
+			t.cmdPanner(1, 0),
+			""},
+		{'+',
+			"+",
+			"Grow brush",
+			t.cmdGrowBrush,
+			""},
+		{'-',
+			"-",
+			"Shrink brush",
+			t.cmdShrinkBrush,
+			""},
+		{'b',
+			"B",
+			"Brush shape",
+			t.cmdToggleBrushShape,
+			""},
+		{'j',
+			"J",
+			"Mouse mode",
+			t.cmdToggleDrawMode,
+			""},
+		{'a',
+			"A",
+			"Draw tool",
+			t.cmdNextTool,
+			""},
+		{gocui.KeyTab,
+			"Tab",
+			"Next panel",
+			t.cmdNextPanel,
+			""},
+		{'l',
+			"L",
+			"Lockstep",
+			t.cmdToggleLockstep,
+			""},
+		{'x',
+			"X",
+			"Copy as RLE",
+			t.cmdCopyRLE,
+			""},
+		{'X',
+			"⇧X",
+			"Copy as image",
+			t.cmdCopyImage,
+			""},
+		{'M',
+			"⇧M",
+			"Copy as macrocell",
+			t.cmdCopyMC,
+			""},
+		{'G',
+			"⇧G",
+			"Image label corner",
+			t.cmdNextImageLabel,
+			""},
+		{'d',
+			"D",
+			"Timings",
+			t.cmdToggleTimings,
+			""},
+		{'D',
+			"⇧D",
+			"Neighbour counts",
+			t.cmdToggleNeighbours,
+			""},
+		{'m',
+			"M",
+			"Mouse",
+			t.cmdToggleMouse,
+			""},
+		{'A',
+			"⇧A",
+			"Analyze lifespan",
+			t.cmdAnalyzeLifespan,
+			""},
+		{'v',
+			"V/⇧V",
+			"Place/merge pattern",
+			t.cmdPlacePattern(false),
+			""},
+		{'V',
+			"",
+			"",
+			t.cmdPlacePattern(true),
+			""},
 		{gocui.MouseLeft,
 			"MOUSE",
 			"Settle the cell",
 			t.cmdMouseClick,
-			"battlefield"},
+			panelName(0)},
+		{gocui.MouseRight,
+			"",
+			"",
+			t.cmdMouseErase,
+			panelName(0)},
 	}
-	t.g.SetManagerFunc(t.layout)
+	return append(keys, t.speedPresetKeys()...)
+}
 
-	t.initKeyBindings(t.k)
+//PrintKeys writes the key bindings table with the key names, the descriptions and the views they work in
+func PrintKeys(w io.Writer) error {
+	t := &ConsoleUI{}
+	return t.writeKeys(w, t.defaultKeys())
+}
 
-	return &t
+//writeKeys writes the key bindings table, the bindings listed with the previous one are skipped
+func (t *ConsoleUI) writeKeys(w io.Writer, k []keyBindings) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Key\tDescription\tView")
+	for _, kb := range k {
+		if kb.name == "" {
+			continue
+		}
+		view := kb.viewName
+		if view == "" {
+			view = "any"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", kb.name, kb.descr, view)
+	}
+	return tw.Flush()
 }
 
-func (t *ConsoleUI) initKeyBindings(k []keyBindings) {
+//initKeyBindings registers the key bindings, the failed ones are skipped
+//returns ErrKeyBindings listing the offending keys, nil if all the keys are bound
+func (t *ConsoleUI) initKeyBindings(k []keyBindings) error {
+	var failed []string
 	for _, kb := range k {
 		h := kb.handler
 		if err := t.g.SetKeybinding(kb.viewName, kb.key, gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error { return h(view) }); err != nil {
-			log.Panicln(err)
+			failed = append(failed, fmt.Sprintf("%s (%v)", keyLabel(kb), err))
 		}
 	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrKeyBindings, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+//keyLabel returns the printable key of the binding
+func keyLabel(kb keyBindings) string {
+	if r, ok := kb.key.(rune); ok {
+		return string(r)
+	}
+	if kb.name != "" {
+		return kb.name
+	}
+	return fmt.Sprintf("%v", kb.key)
+}
+
+//Err returns the key bindings error, the UI works with the bindings that succeeded
+func (t *ConsoleUI) Err() error {
+	return t.bindErr
 }
 
-//Register registers the universe object
+//Register registers the universe object, each universe is shown in its own panel
 func (t *ConsoleUI) Register(u *universe.BaseUniverse) {
-	t.u = u
+	p := &panel{u: u, name: panelName(len(t.panels))}
+	if len(t.panels) == 0 {
+		t.u = u
+	} else {
+		if err := t.initKeyBindings([]keyBindings{{gocui.MouseLeft, "", "", t.cmdMouseClick, p.name},
+			{gocui.MouseRight, "", "", t.cmdMouseErase, p.name}}); err != nil && t.bindErr == nil {
+			t.bindErr = err
+		}
+	}
+	if vp := t.savedViewport; len(t.panels) == 0 && vp != nil {
+		o := u.Options()
+		if vp.Width == o.Width && vp.Height == o.Height && vp.CursorX >= 0 && vp.CursorX < o.Width && vp.CursorY >= 0 && vp.CursorY < o.Height {
+			//the pan is normalized by the next drawing
+			p.panX, p.panY = vp.PanX, vp.PanY
+			t.cursorX, t.cursorY = vp.CursorX, vp.CursorY
+		}
+	}
+	t.panels = append(t.panels, p)
+}
+
+//panelName returns the gocui view name of the i-th battlefield panel
+func panelName(i int) string {
+	if i == 0 {
+		return "battlefield"
+	}
+	return fmt.Sprintf("battlefield%d", i+1)
+}
+
+//focused returns the panel of the focused universe
+func (t *ConsoleUI) focused() *panel {
+	return t.panels[t.focus]
+}
+
+//targets returns the universes the simulation commands are applied to
+func (t *ConsoleUI) targets() []universe.Universe {
+	if !t.lockstep {
+		return []universe.Universe{t.u}
+	}
+	universes := make([]universe.Universe, len(t.panels))
+	for i, p := range t.panels {
+		universes[i] = p.u
+	}
+	return universes
 }
 
 //Start starts the main UI loop
 func (t *ConsoleUI) Start() {
+	if t.o.MaxFPS > 0 {
+		go t.renderLoop()
+	}
+	if t.bindErr != nil {
+		t.notify(bindingsNotice)
+	}
 	if err := t.g.MainLoop(); err != nil && err != gocui.ErrQuit {
 		log.Panicln(err)
 	}
+	close(t.done)
 	t.g.Close()
+	if t.o.PrefsFile != "" {
+		if err := savePrefs(t.o.PrefsFile, t.prefs()); err != nil {
+			log.Println("can't save the preferences:", err)
+		}
+	}
+}
+
+//prefs returns the current preferences
+func (t *ConsoleUI) prefs() Prefs {
+	return Prefs{
+		ChangeFlash:   t.changeFlash,
+		SeamIndicator: t.seamIndicator,
+		WrapGhosts:    t.wrapGhosts,
+		Inspect:       t.inspect,
+		BrushSize:     t.brushSize,
+		BrushCircle:   t.brushCircle,
+		HoldToRun:     t.holdToRun,
+		DrawMode:      t.drawMode,
+		SquareCells:   t.squareCells,
+		Viewport:      t.viewport(),
+	}
+}
+
+//viewport returns the viewport of the first battlefield to save, nil if it is not saved
+func (t *ConsoleUI) viewport() *Viewport {
+	if !t.o.SaveViewport || len(t.panels) == 0 {
+		return nil
+	}
+	p := t.panels[0]
+	o := p.u.Options()
+	vp := &Viewport{Width: o.Width, Height: o.Height, PanX: p.panX, PanY: p.panY, CursorX: t.cursorX, CursorY: t.cursorY}
+	return vp
+}
+
+//applyPrefs applies the preferences loaded from the file
+func (t *ConsoleUI) applyPrefs(p Prefs) {
+	t.changeFlash = p.ChangeFlash
+	t.seamIndicator = p.SeamIndicator
+	t.wrapGhosts = p.WrapGhosts
+	t.inspect = p.Inspect
+	t.brushSize = p.BrushSize
+	t.brushCircle = p.BrushCircle
+	t.holdToRun = p.HoldToRun
+	t.drawMode = p.DrawMode
+	t.squareCells = p.SquareCells
+	if t.o.SaveViewport {
+		t.savedViewport = p.Viewport
+	}
 }
 
 //Refresh do the display update
+//if the frame rate is limited, the display is only marked as outdated and the render ticker redraws it later
 func (t *ConsoleUI) Refresh() {
-	t.renderField(t.u.Area())
+	if t.o.MaxFPS > 0 {
+		atomic.StoreInt32(&t.dirty, 1)
+	} else {
+		t.render()
+	}
+}
+
+//render redraws all the universe related panels, the drawing is scheduled to the gocui main loop
+func (t *ConsoleUI) render() {
+	for _, p := range t.panels {
+		t.renderPanel(p)
+	}
 	t.renderConfiguration()
 	t.renderStatus()
+	t.renderTimings()
+	t.renderNeighbours()
+}
+
+//throttled returns true if the drawing of the field larger than MaxRenderCells is postponed
+//the snapshot copy and the drawing of the huge field take longer than the steps, so it is redrawn once in largeFieldInterval
+//should be called by the gocui main loop
+func (t *ConsoleUI) throttled(p *panel) bool {
+	o := p.u.Options()
+	if t.o.MaxRenderCells <= 0 || o.Width*o.Height <= t.o.MaxRenderCells {
+		return false
+	}
+	if !p.largeNotified {
+		p.largeNotified = true
+		t.notify(largeFieldNotice)
+	}
+	if wait := time.Until(p.nextRender); wait > 0 {
+		if !p.renderPending {
+			p.renderPending = true
+			time.AfterFunc(wait, t.Refresh)
+		}
+		return true
+	}
+	p.nextRender, p.renderPending = time.Now().Add(largeFieldInterval), false
+	return false
 }
 
-//renderField renders the main "battle field" panel
-func (t *ConsoleUI) renderField(a universe.Area) {
+//renderPanel checks if the simulation is finished and redraws the battlefield panel with the overlay
+//it's called by the universe goroutines and the timers, the panel state is accessed by the gocui main loop only
+func (t *ConsoleUI) renderPanel(p *panel) {
+	t.g.Update(func(*gocui.Gui) error {
+		mode := p.u.Status().RunningMode
+		if mode == universe.RunningStateFinished && p.lastMode != universe.RunningStateFinished {
+			t.notifyFinished()
+		}
+		p.lastMode = mode
+		t.drawPanel(p)
+		return nil
+	})
+}
+
+//drawPanel prepares the overlay and draws the field of the panel, should be called by the gocui main loop
+func (t *ConsoleUI) drawPanel(p *panel) {
+	if t.throttled(p) {
+		return
+	}
+	if p.scrubbing {
+		if past, ok := p.u.Generation(p.scrubGen); ok {
+			//the live field overlays don't apply to the past generation
+			t.renderField(p, past, fieldOverlay{})
+			return
+		}
+		//the generation is dropped from the history meanwhile
+		p.scrubbing = false
+	}
+	a := p.u.Area()
+	overlay := fieldOverlay{}
+	iteration := p.u.Status().IterationNum
+	if t.seamIndicator {
+		overlay.seams = wrappedEdges(p.u.Boundary())
+	}
+	if t.wrapGhosts {
+		overlay.ghosts = wrappedEdges(p.u.Boundary())
+	}
+	if iteration != p.renderedIteration {
+		if t.changeFlash {
+			overlay.changes = changes(p.u)
+			//the next frame is drawn without the highlighting
+			time.AfterFunc(changeFlashDuration, t.Refresh)
+		}
+		if overlay.seams != 0 {
+			born, _ := p.u.Changes()
+			if crossed := crossedEdges(a, born, overlay.seams); crossed != 0 {
+				p.crossed = crossed
+				p.crossedUntil = time.Now().Add(seamFlashDuration)
+				time.AfterFunc(seamFlashDuration, t.Refresh)
+			}
+		}
+	}
+	if t.preview && p.u.Status().RunningMode != universe.RunningStateRun {
+		//the running field changes too fast to study the preview
+		overlay.changes = forecast(p.u, a)
+	}
+	if t.compare {
+		overlay.reference = t.reference
+	}
+	if time.Now().Before(p.crossedUntil) {
+		overlay.crossed = p.crossed
+	}
+	p.renderedIteration = iteration
+	t.renderField(p, a, overlay)
+}
+
+//wrappedEdges returns the edges connected to the opposite ones by the boundary
+func wrappedEdges(b universe.Boundary) (e edges) {
+	if b.WrapX() {
+		e |= edgeLeft | edgeRight
+	}
+	if b.WrapY() {
+		e |= edgeTop | edgeBottom
+	}
+	return
+}
+
+//cellEdges returns the field edges the cell at x, y lies on
+func cellEdges(x int, y int, width int, height int) (e edges) {
+	if x == 0 {
+		e |= edgeLeft
+	}
+	if x == width-1 {
+		e |= edgeRight
+	}
+	if y == 0 {
+		e |= edgeTop
+	}
+	if y == height-1 {
+		e |= edgeBottom
+	}
+	return
+}
+
+//ghostCell checks if the cell directly across any of the edges e is live, so it's going to wrap in next to x, y
+func ghostCell(a universe.Area, x int, y int, e edges) bool {
+	return e&edgeLeft != 0 && bool(a.Entities[y][a.Width-1]) ||
+		e&edgeRight != 0 && bool(a.Entities[y][0]) ||
+		e&edgeTop != 0 && bool(a.Entities[a.Height-1][x]) ||
+		e&edgeBottom != 0 && bool(a.Entities[0][x])
+}
+
+//crossedEdges returns the wrapped edges where the cells were born next to the live cells on the opposite edge
+func crossedEdges(a universe.Area, born []universe.Point, seams edges) (crossed edges) {
+	alive := func(x int, y int) bool {
+		x, y = (x+a.Width)%a.Width, (y+a.Height)%a.Height
+		return bool(a.Entities[y][x])
+	}
+	//checks the 3 cells across the edge
+	across := func(x int, y int, dx int, dy int) bool {
+		return alive(x+dx-dy, y+dy-dx) || alive(x+dx, y+dy) || alive(x+dx+dy, y+dy+dx)
+	}
+	for _, p := range born {
+		e := cellEdges(p.X, p.Y, a.Width, a.Height) & seams
+		if e&edgeLeft != 0 && across(p.X, p.Y, -1, 0) {
+			crossed |= edgeLeft
+		}
+		if e&edgeRight != 0 && across(p.X, p.Y, 1, 0) {
+			crossed |= edgeRight
+		}
+		if e&edgeTop != 0 && across(p.X, p.Y, 0, -1) {
+			crossed |= edgeTop
+		}
+		if e&edgeBottom != 0 && across(p.X, p.Y, 0, 1) {
+			crossed |= edgeBottom
+		}
+	}
+	return
+}
+
+//changes returns the cells changed on the last step of the universe, born cells are mapped to true, died ones to false
+func changes(u universe.Universe) map[universe.Point]bool {
+	return changeMap(u.Changes())
+}
+
+//forecast returns the cells changed by the next step of the area of the universe, born cells are mapped to true
+func forecast(u universe.Universe, a universe.Area) map[universe.Point]bool {
+	return changeMap(universe.Forecast(a, u.Rule(), u.Boundary()))
+}
+
+//changeMap maps the born cells to true and the died ones to false
+func changeMap(born []universe.Point, died []universe.Point) map[universe.Point]bool {
+	changes := make(map[universe.Point]bool, len(born)+len(died))
+	for _, p := range born {
+		changes[p] = true
+	}
+	for _, p := range died {
+		changes[p] = false
+	}
+	return changes
+}
+
+//renderLoop redraws the display at most MaxFPS times per second if it is outdated
+//so the simulation speed is not limited by the terminal speed
+func (t *ConsoleUI) renderLoop() {
+	ticker := time.NewTicker(time.Second / time.Duration(t.o.MaxFPS))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if atomic.CompareAndSwapInt32(&t.dirty, 1, 0) {
+				t.render()
+			}
+		case <-t.done:
+			return
+		}
+	}
+}
+
+//notifyFinished signals the end of the simulation by the terminal bell and the header flash
+func (t *ConsoleUI) notifyFinished() {
+	if t.o.Bell {
+		bell()
+	}
+	t.flashHeader(gocui.ColorRed)
+	time.AfterFunc(headerFlashDuration, func() {
+		t.flashHeader(t.headerBg)
+	})
+}
+
+//flashHeader changes the header background color
+func (t *ConsoleUI) flashHeader(color gocui.Attribute) {
+	t.g.Update(func(g *gocui.Gui) error {
+		if v, e := g.View("header"); e == nil {
+			v.BgColor = color
+		}
+		return nil
+	})
+}
+
+//renderField renders the "battle field" panel with the overlay
+func (t *ConsoleUI) renderField(p *panel, a universe.Area, overlay fieldOverlay) {
 
 	t.g.Update(func(g *gocui.Gui) error {
-		v, e := g.View("battlefield")
+		v, e := g.View(p.name)
 		if e != nil {
 			return e
 		}
+		start := time.Now()
+		defer func() {
+			p.renderTime = time.Since(start)
+		}()
 		//the entire field is redrawing at once now
 		//this terminal driver allows to redraw only changed chars
 		//there is an opportunity to speed up with a selective redraw
 		v.Clear()
 
-		crop := false
 		maxW, maxH := v.Size()
-		if a.Width > maxW || a.Height > maxH {
-			crop = true
+		//the square cells take two columns, so the visible field is twice narrower
+		cellW := 1
+		if t.squareCells {
+			cellW = 2
 		}
+		p.cellW = cellW
+		crop := a.Width*cellW > maxW || a.Height > maxH
+		//the visible window of the field, the last line is taken by the crop indicator
+		visW, visH := min(a.Width, maxW/cellW), a.Height
+		if crop {
+			visH = min(a.Height, maxH-1)
+		}
+		flip := bottomUp(p.u)
+		boundary := p.u.Boundary()
+		p.width, p.height, p.wrapX, p.wrapY = a.Width, a.Height, boundary.WrapX(), boundary.WrapY()
+		anchorX, anchorY := cropOffset(t.o.CropAnchor, a.Width-visW, a.Height-visH)
+		p.offsetX = windowAxis(anchorX+p.panX, a.Width, visW, p.wrapX)
+		p.offsetY = windowAxis(anchorY+p.panY, a.Height, visH, p.wrapY)
+		//the pan is normalized, so panning back from the fixed edge moves the window at once
+		p.panX, p.panY = p.offsetX-anchorX, p.offsetY-anchorY
 
 		var b bytes.Buffer
 
-		for i, l := range a.Entities {
-			//discard the data outside the view area
-			if i >= maxH {
-				break
-			}
+		for i := 0; i < visH; i++ {
 			//line feed char
 			if i != 0 {
 				b.WriteByte(10)
 			}
-			if crop && i == (maxH-1) {
-				b.WriteString(aurora.Red("The field size is larger than the viewing area").BgBlack().String())
-				break
+			y := wrapAxis(i+p.offsetY, a.Height, p.wrapY)
+			if flip {
+				y = a.Height - 1 - y
 			}
-			for j, e := range l {
-				if j >= maxW {
-					break
-				}
-				if e {
-					b.WriteString(t.liveFiller)
+			for j := 0; j < visW; j++ {
+				x := wrapAxis(j+p.offsetX, a.Width, p.wrapX)
+				e := a.Entities[y][x]
+				var filler string
+				if ref, ok := referenceCell(overlay.reference, x, y); ok && ref != e {
+					if e {
+						filler = t.extraFiller
+					} else {
+						filler = t.missingFiller
+					}
+				} else if born, ok := overlay.changes[universe.Point{X: x, Y: y}]; ok {
+					if born {
+						filler = t.bornFiller
+					} else {
+						filler = t.diedFiller
+					}
+				} else if e && a.Colors != nil {
+					filler = t.colorFillers[a.Colors[y][x]]
+				} else if e {
+					filler = t.liveFiller
+				} else if ghostCell(a, x, y, cellEdges(x, y, a.Width, a.Height)&overlay.ghosts) {
+					filler = t.ghostFiller
+				} else if seam := cellEdges(x, y, a.Width, a.Height) & overlay.seams; seam != 0 {
+					if seam&overlay.crossed != 0 {
+						filler = t.seamFlash
+					} else {
+						filler = t.seamFiller
+					}
 				} else {
-					b.WriteString(t.deadFiller)
+					filler = t.deadFiller
+				}
+				for k := 0; k < cellW; k++ {
+					b.WriteString(filler)
 				}
 			}
 		}
+		if crop && maxH > 0 {
+			if visH != 0 {
+				b.WriteByte(10)
+			}
+			if utf8.RuneCountInString(t.o.CropMessage) <= maxW {
+				b.WriteString(t.cropMessage)
+			} else {
+				b.WriteString(t.cropGlyph)
+			}
+		}
 		_, _ = fmt.Fprint(v, b.String())
 		return nil
 	})
 }
 
-//renderStatus renders the status panel
+//referenceCell returns the reference cell state, ok is false if there is no reference or the cell is outside it
+func referenceCell(ref universe.Area, x int, y int) (live universe.Cell, ok bool) {
+	if y >= len(ref.Entities) || x >= len(ref.Entities[y]) {
+		return false, false
+	}
+	return ref.Entities[y][x], true
+}
+
+//SetReference sets the area the field is compared with and turns the comparison on
+func (t *ConsoleUI) SetReference(a universe.Area) {
+	t.reference = a
+	t.compare = true
+	t.Refresh()
+}
+
+//cropOffset returns the top left corner of the visible window by the anchor, dx and dy are the hidden parts of the field
+func cropOffset(anchor string, dx int, dy int) (x int, y int) {
+	switch anchor {
+	case CropAnchorCenter:
+		return dx / 2, dy / 2
+	case CropAnchorBottomRight:
+		return dx, dy
+	}
+	return 0, 0
+}
+
+//windowAxis returns the visible window offset on the axis of the size, the offset is wrapped around the wrapped axis
+//and kept within the field on the fixed one
+func windowAxis(offset int, size int, visible int, wrap bool) int {
+	if wrap {
+		return wrapAxis(offset, size, true)
+	}
+	if offset > size-visible {
+		offset = size - visible
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
+
+//wrapAxis wraps the coordinate around the axis of the size if wrap is set
+func wrapAxis(v int, size int, wrap bool) int {
+	if wrap && size > 0 {
+		return (v%size + size) % size
+	}
+	return v
+}
+
+//toField returns the displayed field column and row of the battlefield view position
+func (p *panel) toField(vx int, vy int) (x int, y int) {
+	return wrapAxis(vx/max(p.cellW, 1)+p.offsetX, p.width, p.wrapX), wrapAxis(vy+p.offsetY, p.height, p.wrapY)
+}
+
+//toView returns the battlefield view position of the displayed field column and row
+func (p *panel) toView(x int, y int) (vx int, vy int) {
+	return wrapAxis(x-p.offsetX, p.width, p.wrapX) * max(p.cellW, 1), wrapAxis(y-p.offsetY, p.height, p.wrapY)
+}
+
+//min returns the smaller of a and b
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+//max returns the larger of a and b
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+//headerColors returns the header text and background colors, the unset options are replaced by the defaults
+func headerColors(o *Options) (fg gocui.Attribute, bg gocui.Attribute) {
+	if o.HeaderText == "" {
+		o.HeaderText = DefHeaderText
+	}
+	fg, ok := attributes[o.HeaderColor]
+	if !ok {
+		fg = attributes[DefHeaderColor]
+	}
+	bg, ok = attributes[o.HeaderBgColor]
+	if !ok {
+		bg = attributes[DefHeaderBgColor]
+	}
+	return
+}
+
+//cropIndicator returns the colored crop message and glyph, the unset options are replaced by the defaults
+func cropIndicator(o *Options) (message string, glyph string) {
+	if o.CropMessage == "" {
+		o.CropMessage = DefCropMessage
+	}
+	if o.CropGlyph == "" {
+		o.CropGlyph = DefCropGlyph
+	}
+	c, ok := colors[o.CropColor]
+	if !ok {
+		c = colors[DefCropColor]
+	}
+	message = aurora.Colorize(o.CropMessage, c|aurora.BlackBg).String()
+	glyph = aurora.Colorize(o.CropGlyph, c|aurora.BlackBg).String()
+	return
+}
+
+//renderStatus renders the status panel, the configured metrics are followed by the lines of the active modes
 func (t *ConsoleUI) renderStatus() {
-	s := t.u.Status()
 	t.g.Update(func(g *gocui.Gui) error {
+		s := t.u.Status()
 		if v, e := t.g.View("status"); e == nil {
 			v.Clear()
-			_, _ = fmt.Fprintln(v, t.renderProp("Step", "%v", s.IterationNum))
-			_, _ = fmt.Fprintln(v, t.renderProp("Live Cells", "%v", s.LiveCells))
-			_, _ = fmt.Fprintln(v, t.renderProp("Evaluation time", "%v", s.IterationTime.Round(time.Microsecond)))
-			_, _ = fmt.Fprintln(v, t.renderProp("Mode", "%v", runningStateDescr[s.RunningMode]))
+			for _, m := range t.o.StatusMetrics {
+				if line := t.renderMetric(m, s); line != "" {
+					_, _ = fmt.Fprintln(v, line)
+				}
+			}
+			if len(t.panels) > 1 {
+				_, _ = fmt.Fprintln(v, t.renderProp("Lockstep", "%v", t.lockstep))
+			}
+			if t.inspect {
+				neighbours, next := t.explainCell()
+				_, _ = fmt.Fprintln(v, t.renderProp("Cell", "%v, %v", t.cursorX, t.displayY(t.cursorY)))
+				_, _ = fmt.Fprintln(v, t.renderProp("Neighbours", "%v", neighbours))
+				_, _ = fmt.Fprintln(v, t.renderProp("Next step", "%v", next))
+			}
+			if t.preview {
+				_, _ = fmt.Fprintln(v, t.renderProp("Preview", "next step, N applies it"))
+			}
+			if p := t.focused(); p.scrubbing {
+				first, last, _ := p.u.History()
+				_, _ = fmt.Fprintln(v, t.renderProp("Scrub", "%v of %v-%v, ⇧F forks", p.scrubGen, first, last))
+			}
+			if t.compare {
+				_, _ = fmt.Fprintln(v, t.renderProp("Differs", "%v", t.differs()))
+			}
+			if la := t.focused().lifespan; la != nil {
+				t.renderLifespan(v, la)
+			}
+			if s.RunningMode == universe.RunningStateFinished && s.FinishReason != universe.FinishReasonNone {
+				_, _ = fmt.Fprintln(v, t.renderProp("Reason", "%v", s.FinishReason))
+				if s.Period > 0 {
+					_, _ = fmt.Fprintln(v, t.renderProp("Period", "%v", s.Period))
+				}
+			}
 		}
 		return nil
 	})
 }
 
-//renderConfiguration renders the configuration panel
-func (t *ConsoleUI) renderConfiguration() {
-	//it needs to call Update when calls from goroutine
-	t.g.Update(func(g *gocui.Gui) error {
-		c := t.u.Options()
+//renderLifespan writes the lifespan analysis lines to the status panel
+func (t *ConsoleUI) renderLifespan(v io.Writer, la *lifespanAnalysis) {
+	if !la.done {
+		_, _ = fmt.Fprintln(v, t.renderProp("Lifespan", "analyzing from step %v…", la.step))
+		return
+	}
+	_, _ = fmt.Fprintln(v, t.renderProp("Lifespan", "%v generations from step %v", la.l.Generations, la.step))
+	_, _ = fmt.Fprintln(v, t.renderProp("Final", "%v cells, %v", la.l.FinalPopulation, classification(la.l.FinishReason)))
+	if la.l.Period > 1 {
+		_, _ = fmt.Fprintln(v, t.renderProp("Final period", "%v", la.l.Period))
+	}
+}
+
+//renderTimings renders the timings panel of the focused universe if it is shown
+//the step evaluation (the neighbours counting), the area snapshot publishing (the buffer swap) and the field drawing are timed
+//the histogram of the last steps evaluation shows the pauses the average hides
+//the memory is the engine estimate of the field representation
+func (t *ConsoleUI) renderTimings() {
+	t.g.Update(func(g *gocui.Gui) error {
+		s := t.u.Status()
+		if v, e := g.View("timings"); e == nil {
+			v.Clear()
+			_, _ = fmt.Fprintln(v, t.renderProp("Step", "%v", s.IterationNum))
+			_, _ = fmt.Fprintln(v, t.renderProp("Neighbours", "%v", s.IterationTime.Round(time.Microsecond)))
+			_, _ = fmt.Fprintln(v, t.renderProp("Buffer swap", "%v", s.PublishTime.Round(time.Microsecond)))
+			_, _ = fmt.Fprintln(v, t.renderProp("Render", "%v", t.focused().renderTime.Round(time.Microsecond)))
+			_, _ = fmt.Fprintln(v, t.renderProp("Memory", "%v", formatBytes(s.Memory)))
+			_, _ = fmt.Fprintln(v, t.renderProp("History", "%v", formatBytes(s.HistoryMemory)))
+			_, _ = fmt.Fprintf(v, " Last %v steps:\n", universe.DefHistogramSteps)
+			for i, n := range s.StepHistogram {
+				_, _ = fmt.Fprintln(v, t.renderProp(histogramBucketName(i), "%v", n))
+			}
+		}
+		return nil
+	})
+}
+
+//renderNeighbours renders the neighbours panel of the focused universe if it is shown
+//the cells are counted by the live neighbours, the counts giving the birth (B) and the survival (S) by the rule are marked
+//the whole field is walked, so the counts are calculated only while the panel is shown
+func (t *ConsoleUI) renderNeighbours() {
+	t.g.Update(func(g *gocui.Gui) error {
+		if !t.neighbours {
+			return nil
+		}
+		if v, e := g.View("neighbours"); e == nil {
+			c := universe.CountNeighbours(t.u.Area(), t.u.Boundary())
+			r := t.u.Rule()
+			v.Clear()
+			for n := range c.Live {
+				marks := ""
+				if r.Birth[n] {
+					marks += " B"
+				}
+				if r.Survive[n] {
+					marks += " S"
+				}
+				_, _ = fmt.Fprintln(v, t.renderProp(strconv.Itoa(n), "%v live, %v dead%v", c.Live[n], c.Dead[n], marks))
+			}
+		}
+		return nil
+	})
+}
+
+//histogramBucketName returns the duration range of the step histogram bucket
+func histogramBucketName(i int) string {
+	if i < len(universe.StepHistogramBounds) {
+		return "≤" + universe.StepHistogramBounds[i].String()
+	}
+	return ">" + universe.StepHistogramBounds[len(universe.StepHistogramBounds)-1].String()
+}
+
+//formatBytes returns the bytes count with the binary unit prefix
+func formatBytes(n int) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := unit, 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+//formatInterval returns the interval with the target steps per second, the max speed label for the negligible intervals
+func formatInterval(d time.Duration) string {
+	if d < minVisibleInterval {
+		return maxSpeedDescr
+	}
+	rate := float64(time.Second) / float64(d)
+	if rate >= 10 {
+		return fmt.Sprintf("%v (%.0f/s)", d.Round(time.Microsecond), rate)
+	}
+	return fmt.Sprintf("%v (%.1f/s)", d.Round(time.Microsecond), rate)
+}
+
+//renderConfiguration renders the configuration panel
+func (t *ConsoleUI) renderConfiguration() {
+	//it needs to call Update when calls from goroutine
+	t.g.Update(func(g *gocui.Gui) error {
+		c := t.u.Options()
 		if v, e := g.View("configuration"); e == nil {
 			v.Clear()
 			_, _ = fmt.Fprintln(v, t.renderProp("Dimension", "%v x %v", c.Width, c.Height))
-			_, _ = fmt.Fprintln(v, t.renderProp("Interval", "%v", c.Interval))
+			if preset := t.speedPresetName(c.Interval); preset != "" {
+				_, _ = fmt.Fprintln(v, t.renderProp("Interval", "%v [%v]", formatInterval(c.Interval), preset))
+			} else {
+				_, _ = fmt.Fprintln(v, t.renderProp("Interval", "%v", formatInterval(c.Interval)))
+			}
 			_, _ = fmt.Fprintln(v, t.renderProp("Iterations", "%v steps", c.MaxSteps))
+			if s := t.u.Source(); s.Name != "" {
+				_, _ = fmt.Fprintln(v, t.renderProp("Source", "%v", s.Name))
+				if s.Path != "" {
+					_, _ = fmt.Fprintln(v, t.renderProp("File", "%v", shortPath(s.Path, sourcePathWidth)))
+				}
+			}
 			propNames := make([]string, 0, len(c.Advanced))
 			for k := range c.Advanced {
 				propNames = append(propNames, k)
@@ -212,6 +1324,15 @@ func (t *ConsoleUI) renderConfiguration() {
 	})
 }
 
+//shortPath returns the path shortened to width runes keeping its end
+func shortPath(path string, width int) string {
+	r := []rune(path)
+	if len(r) <= width {
+		return path
+	}
+	return "…" + string(r[len(r)-width+1:])
+}
+
 //renderProp render the properties to the string with colors
 func (t *ConsoleUI) renderProp(name string, valueformat string, values ...interface{}) string {
 	return fmt.Sprintf(" "+aurora.Colorize(name, aurora.GreenFg).String()+": "+valueformat, values...)
@@ -233,11 +1354,14 @@ func (t *ConsoleUI) layout(g *gocui.Gui) error {
 		}
 		_ = g.DeleteView("configuration")
 		_ = g.DeleteView("status")
-		_ = g.DeleteView("battlefield")
+		for _, p := range t.panels {
+			_ = g.DeleteView(p.name)
+			p.fieldW, p.fieldH = 0, 0
+		}
 		return nil
 
 	} else {
-		if _, err := t.headerLayout(g, 3, "This is \"The Life\" game simulation"); err != nil {
+		if _, err := t.headerLayout(g, 3, t.headerText()); err != nil {
 			if err != gocui.ErrUnknownView {
 				return err
 			}
@@ -262,15 +1386,51 @@ func (t *ConsoleUI) layout(g *gocui.Gui) error {
 		t.renderStatus()
 	}
 
-	if v, err := g.SetView("battlefield", leftColumnWidth+1, 3, maxX-1, maxY-5); err != nil {
-		if err != gocui.ErrUnknownView || v == nil {
-			return err
+	//the panels share the width equally
+	panelWidth := (maxX - 1 - leftColumnWidth) / len(t.panels)
+	for i, p := range t.panels {
+		x0 := leftColumnWidth + 1 + i*panelWidth
+		v, err := g.SetView(p.name, x0, 3, x0+panelWidth-1, maxY-5)
+		if err != nil {
+			if err != gocui.ErrUnknownView || v == nil {
+				return err
+			}
+			v.Frame = true
+		}
+		v.Title = t.panelTitle(i)
+		//the field is redrawn here on resizing only, the universe changes are drawn on refreshing
+		if w, h := v.Size(); w != p.fieldW || h != p.fieldH {
+			p.fieldW, p.fieldH = w, h
+			t.renderField(p, p.u.Area(), fieldOverlay{})
+		}
+	}
+
+	//the timings panel is shown over the top right corner of the battlefield
+	if t.timings {
+		if v, err := g.SetView("timings", maxX-timingsWidth-1, 3, maxX-1, 3+7+1+universe.StepHistogramBuckets); err != nil {
+			if err != gocui.ErrUnknownView || v == nil {
+				return err
+			}
+			v.Title = "Timings"
+			v.Frame = true
+			t.renderTimings()
 		}
-		v.Title = "Battle Field"
-		v.Frame = true
-		t.renderField(t.u.Area())
 	} else {
-		t.renderField(t.u.Area())
+		_ = g.DeleteView("timings")
+	}
+
+	//the neighbours panel is shown over the bottom right corner of the battlefield
+	if t.neighbours {
+		if v, err := g.SetView("neighbours", maxX-neighboursWidth-1, maxY-5-len(universe.NeighbourCounts{}.Live)-1, maxX-1, maxY-5); err != nil {
+			if err != gocui.ErrUnknownView || v == nil {
+				return err
+			}
+			v.Title = "Neighbours"
+			v.Frame = true
+			t.renderNeighbours()
+		}
+	} else {
+		_ = g.DeleteView("neighbours")
 	}
 
 	if v, err := g.SetView("help", -1, maxY-5, maxX, maxY-3); err != nil {
@@ -278,15 +1438,31 @@ func (t *ConsoleUI) layout(g *gocui.Gui) error {
 			return err
 		}
 		v.Frame = false
+	}
+	//the help is redrawn on each layout to reflect the mouse capture state
+	if v, err := g.View("help"); err == nil {
+		v.Clear()
 		b := bytes.Buffer{}
 		b.WriteString("KEYBINDINGS: ")
-		for i, k := range t.k {
-			if i != 0 {
+		listed := 0
+		for _, k := range t.k {
+			//the extra bindings of the listed command have no name, the mouse bindings are hidden if the mouse is off
+			if k.name == "" || !g.Mouse && k.key == gocui.MouseLeft {
+				continue
+			}
+			if listed != 0 {
 				b.WriteString(", ")
 			}
+			listed++
 			b.WriteString(aurora.Green(k.name).String())
 			b.WriteString(": ")
 			b.WriteString(k.descr)
+			if k.key == 'm' {
+				b.WriteString(map[bool]string{true: " (on)", false: " (off, select text)"}[g.Mouse])
+			}
+			if k.key == 'j' {
+				b.WriteString(map[bool]string{true: " (draw)", false: " (toggle)"}[t.drawMode])
+			}
 		}
 		_, _ = fmt.Fprintln(v, b.String())
 	}
@@ -294,64 +1470,769 @@ func (t *ConsoleUI) layout(g *gocui.Gui) error {
 	return nil
 }
 
+//panelTitle returns the title of the i-th battlefield panel, the panels are distinguished by the rule
+func (t *ConsoleUI) panelTitle(i int) string {
+	if len(t.panels) == 1 {
+		return "Battle Field"
+	}
+	title := fmt.Sprintf("Battle Field %d: %v", i+1, t.panels[i].u.Rule())
+	if i == t.focus {
+		title = "* " + title
+	}
+	return title
+}
+
 //headerLayout creates the window header with center positioning message
 func (t *ConsoleUI) headerLayout(g *gocui.Gui, height int, text string) (v *gocui.View, err error) {
 	maxX, _ := g.Size()
 	if v, err = g.SetView("header", -1, -1, maxX+1, height); err != nil {
 		if err == gocui.ErrUnknownView && v != nil {
 			v.Frame = false
-			v.BgColor = gocui.ColorCyan
-			v.FgColor = gocui.ColorBlack
+			v.BgColor = t.headerBg
+			v.FgColor = t.headerFg
 		}
 	}
 	if v != nil {
 		v.Clear()
-		if maxX < len(text) {
-			panic(fmt.Sprintf("Terminal width is too small: %v", maxX))
+		//the text wider than the terminal is cut
+		runes := []rune(text)
+		if len(runes) > maxX {
+			runes = runes[:max(maxX, 0)]
+			if len(runes) > 0 {
+				runes[len(runes)-1] = '…'
+			}
 		}
-		_, _ = fmt.Fprintln(v, strings.Repeat("\n", height/2+1)+strings.Repeat(" ", (maxX-len(text))/2)+text)
+		_, _ = fmt.Fprintln(v, strings.Repeat("\n", height/2+1)+strings.Repeat(" ", (maxX-len(runes))/2)+string(runes))
 	}
 	return
 }
 
+//headerText returns the notice if it is not expired or the configured header text
+func (t *ConsoleUI) headerText() string {
+	if t.notice != "" && time.Now().Before(t.noticeUntil) {
+		return t.notice
+	}
+	return t.o.HeaderText
+}
+
+//notify shows the message in the header for noticeDuration
+func (t *ConsoleUI) notify(message string) {
+	t.notice, t.noticeUntil = message, time.Now().Add(noticeDuration)
+	//the empty update runs the layout restoring the header message
+	time.AfterFunc(noticeDuration, func() {
+		t.g.Update(func(*gocui.Gui) error { return nil })
+	})
+}
+
 //cmdQuit calls by gocui key handlers and do the quit
 func (t *ConsoleUI) cmdQuit(_ *gocui.View) error {
 	return gocui.ErrQuit
 }
 
 //cmdNextRound calls by gocui key handler and calls the Next Round command in the Universe
+//the running simulation is paused and advanced by one step, the paused one is advanced staying paused
 func (t *ConsoleUI) cmdNextRound(_ *gocui.View) error {
-	t.u.Step()
+	t.stopSharedTicker()
+	for _, u := range t.targets() {
+		u.Step()
+	}
 	return nil
 }
 
 //cmdRun calls by gocui key handler and calls the Run command in the Universe
+//the universes in lockstep are run by the shared ticker, so the panels always show the same generation
 func (t *ConsoleUI) cmdRun(_ *gocui.View) error {
-	t.u.Run()
+	if t.holdToRun {
+		t.holdRun()
+		return nil
+	}
+	if t.lockstep && len(t.panels) > 1 {
+		t.runSharedTicker()
+		return nil
+	}
+	for _, u := range t.targets() {
+		u.Run()
+	}
+	return nil
+}
+
+//holdRun starts the simulation or extends the running window of the hold-to-run mode
+func (t *ConsoleUI) holdRun() {
+	t.holdUntil = time.Now().Add(holdRunWindow)
+	if t.holdTimer != nil {
+		return
+	}
+	t.holdTimer = time.AfterFunc(holdRunWindow, t.holdCheck)
+	if t.lockstep && len(t.panels) > 1 {
+		t.runSharedTicker()
+		return
+	}
+	for _, u := range t.targets() {
+		u.Run()
+	}
+}
+
+//holdCheck stops the simulation when the hold-to-run window is expired, the window extended meanwhile is waited for
+//calls by the timer, the UI state is accessed by the main loop
+func (t *ConsoleUI) holdCheck() {
+	t.g.Update(func(*gocui.Gui) error {
+		if left := time.Until(t.holdUntil); left > 0 {
+			t.holdTimer = time.AfterFunc(left, t.holdCheck)
+			return nil
+		}
+		t.holdTimer = nil
+		return t.cmdStop(nil)
+	})
+}
+
+//cmdToggleHoldToRun calls by gocui key handler and switches the run key between starting the simulation and running while held
+func (t *ConsoleUI) cmdToggleHoldToRun(_ *gocui.View) error {
+	t.holdToRun = !t.holdToRun
+	if t.holdToRun {
+		t.notify("Hold R to run")
+	} else {
+		t.notify("R starts the run")
+	}
+	return nil
+}
+
+//cmdFastRun calls by gocui key handler and calls the Fast Run command in the Universe
+func (t *ConsoleUI) cmdFastRun(_ *gocui.View) error {
+	t.stopSharedTicker()
+	for _, u := range t.targets() {
+		u.FastRun()
+	}
 	return nil
 }
 
 //cmdStop calls by gocui key handler and calls the Stop command in the Universe
 func (t *ConsoleUI) cmdStop(_ *gocui.View) error {
-	t.u.Stop()
+	t.stopSharedTicker()
+	for _, u := range t.targets() {
+		u.Stop()
+	}
 	return nil
 }
 
 //cmdClear calls by gocui key handler and calls the Clear command in the Universe
 func (t *ConsoleUI) cmdClear(_ *gocui.View) error {
-	t.u.Clear()
+	t.stopSharedTicker()
+	for _, u := range t.targets() {
+		u.Clear()
+	}
 	return nil
 }
 
 //cmdSettleWithRandom calls by gocui key handler and calls the Settle With Random Cells command in the Universe
 func (t *ConsoleUI) cmdSettleWithRandom(_ *gocui.View) error {
-	t.u.SettleWithRandomData()
+	t.stopSharedTicker()
+	for _, u := range t.targets() {
+		u.SettleWithRandomData()
+	}
+	return nil
+}
+
+//cmdAnalyzeLifespan calls by gocui key handler and runs the current field of the focused universe until it stabilizes
+//the analysis runs in background, the result is shown in the status panel
+func (t *ConsoleUI) cmdAnalyzeLifespan(_ *gocui.View) error {
+	p := t.focused()
+	if p.lifespan != nil && !p.lifespan.done {
+		return nil
+	}
+	la := &lifespanAnalysis{step: p.u.Status().IterationNum}
+	p.lifespan = la
+	a, r, b, maxSteps := p.u.Area(), p.u.Rule(), p.u.Boundary(), p.u.Options().MaxSteps
+	go func() {
+		l := universe.AnalyzeLifespan(a, r, b, maxSteps)
+		t.g.Update(func(*gocui.Gui) error {
+			la.l, la.done = l, true
+			t.renderStatus()
+			return nil
+		})
+	}()
+	t.renderStatus()
+	return nil
+}
+
+//cmdInvertAll calls by gocui key handler and inverses the entire field of the target universes
+func (t *ConsoleUI) cmdInvertAll(_ *gocui.View) error {
+	t.stopSharedTicker()
+	for _, u := range t.targets() {
+		u.InvertAll()
+	}
+	return nil
+}
+
+//runSharedTicker steps the universes in lockstep by the shared ticker until it is stopped or all the universes are finished
+//each tick waits for all the steps, the interval is taken from the focused universe options
+func (t *ConsoleUI) runSharedTicker() {
+	if t.tickerStop != nil {
+		return
+	}
+	stop := make(chan bool)
+	t.tickerStop = stop
+	atomic.StoreInt32(&t.sharedRun, 1)
+	universes, focused := t.targets(), t.u
+	go func() {
+		defer t.g.Update(func(*gocui.Gui) error {
+			if t.tickerStop == stop {
+				t.stopSharedTicker()
+			}
+			return nil
+		})
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if universe.StepAll(universes) {
+				return
+			}
+			select {
+			case <-stop:
+				return
+			case <-time.After(focused.Options().Interval):
+			}
+		}
+	}()
+}
+
+//stopSharedTicker stops the shared ticker if it is running, the step in progress is completed
+//should be called by the gocui main loop
+func (t *ConsoleUI) stopSharedTicker() {
+	if t.tickerStop == nil {
+		return
+	}
+	close(t.tickerStop)
+	t.tickerStop = nil
+	atomic.StoreInt32(&t.sharedRun, 0)
+	t.Refresh()
+}
+
+//cmdCopyRLE calls by gocui key handler and copies the field as RLE to the clipboard
+//the pattern is written to the file in the working directory if the clipboard is not available
+func (t *ConsoleUI) cmdCopyRLE(_ *gocui.View) error {
+	b := bytes.Buffer{}
+	if err := universe.SaveRLE(&b, t.u.Area(), t.u.Rule(), t.u.Boundary()); err != nil {
+		t.notify("RLE export failed")
+		return nil
+	}
+	if err := copyToClipboard(b.String()); err == nil {
+		t.notify("Copied to the clipboard as RLE")
+		return nil
+	}
+	name := fmt.Sprintf("simlife-%s.rle", time.Now().Format("20060102-150405"))
+	if err := ioutil.WriteFile(name, b.Bytes(), 0644); err != nil {
+		t.notify("RLE export failed")
+		return nil
+	}
+	t.notify("Saved to " + name)
+	return nil
+}
+
+//cmdCopyMC calls by gocui key handler and copies the field as Golly's macrocell to the clipboard
+//the pattern is written to the file in the working directory if the clipboard is not available
+func (t *ConsoleUI) cmdCopyMC(_ *gocui.View) error {
+	b := bytes.Buffer{}
+	if err := universe.SaveMC(&b, t.u.Area(), t.u.Rule()); err != nil {
+		t.notify("Macrocell export failed")
+		return nil
+	}
+	if err := copyToClipboard(b.String()); err == nil {
+		t.notify("Copied to the clipboard as macrocell")
+		return nil
+	}
+	name := fmt.Sprintf("simlife-%s.mc", time.Now().Format("20060102-150405"))
+	if err := ioutil.WriteFile(name, b.Bytes(), 0644); err != nil {
+		t.notify("Macrocell export failed")
+		return nil
+	}
+	t.notify("Saved to " + name)
+	return nil
+}
+
+//cmdCopyImage calls by gocui key handler and copies the field as PNG image to the clipboard
+//the image is saved to the current directory if there is no image clipboard tool
+func (t *ConsoleUI) cmdCopyImage(_ *gocui.View) error {
+	a := t.u.Area()
+	st := t.u.Status()
+	l := universe.ImageLabel{Corner: t.imageLabel, Generation: st.IterationNum, Population: st.LiveCells, ShowPopulation: t.o.ImageLabelPopulation}
+	b := bytes.Buffer{}
+	if err := universe.SaveLabeledImage(&b, a, imageScale(a), l); err != nil {
+		t.notify("Image export failed")
+		return nil
+	}
+	if err := copyImageToClipboard(b.Bytes()); err == nil {
+		t.notify("Copied to the clipboard as image")
+		return nil
+	}
+	name := fmt.Sprintf("simlife-%s.png", time.Now().Format("20060102-150405"))
+	if err := ioutil.WriteFile(name, b.Bytes(), 0644); err != nil {
+		t.notify("Image export failed")
+		return nil
+	}
+	t.notify("Saved to " + name)
+	return nil
+}
+
+//cmdNextImageLabel calls by gocui key handler and moves the generation label of the copied image to the next corner
+//the label is hidden after the last corner
+func (t *ConsoleUI) cmdNextImageLabel(_ *gocui.View) error {
+	next := universe.LabelCorners[0]
+	for i, c := range universe.LabelCorners {
+		if c == t.imageLabel {
+			next = ""
+			if i+1 < len(universe.LabelCorners) {
+				next = universe.LabelCorners[i+1]
+			}
+		}
+	}
+	t.imageLabel = next
+	if next == "" {
+		t.notify("Image label: off")
+	} else {
+		t.notify("Image label: " + next)
+	}
+	return nil
+}
+
+//imageScale returns the pixels per cell of the copied image, the cells are shrunk to keep the image within maxImageSize
+func imageScale(a universe.Area) int {
+	return max(1, min(imageCellSize, maxImageSize/max(1, max(a.Width, a.Height))))
+}
+
+//cmdToggleMouse calls by gocui key handler and toggles the mouse capture
+//the terminal text selection works while the mouse is not captured
+func (t *ConsoleUI) cmdToggleMouse(_ *gocui.View) error {
+	t.g.Mouse = !t.g.Mouse
+	//gocui applies the input options on the main loop start only, the same mode is set here
+	mode := termbox.InputAlt
+	if t.g.InputEsc {
+		mode = termbox.InputEsc
+	}
+	if t.g.Mouse {
+		mode |= termbox.InputMouse
+	}
+	termbox.SetInputMode(mode)
+	return nil
+}
+
+//cmdToggleTimings calls by gocui key handler and shows or hides the timings panel
+func (t *ConsoleUI) cmdToggleTimings(_ *gocui.View) error {
+	t.timings = !t.timings
+	return nil
+}
+
+//cmdToggleNeighbours calls by gocui key handler and shows or hides the neighbours panel
+func (t *ConsoleUI) cmdToggleNeighbours(_ *gocui.View) error {
+	t.neighbours = !t.neighbours
+	return nil
+}
+
+//cmdCountComponents calls by gocui key handler and calls the Count Components command in the Universe
+//the clusters are counted with the configured connectivity and the active boundary
+func (t *ConsoleUI) cmdCountComponents(_ *gocui.View) error {
+	t.u.CountComponents(t.o.Connectivity)
+	return nil
+}
+
+//cmdToggleChangeFlash calls by gocui key handler and toggles the highlighting of the cells changed on the last step
+func (t *ConsoleUI) cmdToggleChangeFlash(_ *gocui.View) error {
+	t.changeFlash = !t.changeFlash
+	return nil
+}
+
+//cmdNextRule calls by gocui key handler and switches the Universe to the next preset rule
+func (t *ConsoleUI) cmdNextRule(_ *gocui.View) error {
+	current := t.u.Rule().String()
+	next := universe.PresetRules[0]
+	for i, r := range universe.PresetRules {
+		if r.String() == current {
+			next = universe.PresetRules[(i+1)%len(universe.PresetRules)]
+			break
+		}
+	}
+	t.u.SetRule(next)
+	return nil
+}
+
+//cmdNextBoundary calls by gocui key handler and switches the Universe to the next boundary mode
+func (t *ConsoleUI) cmdNextBoundary(_ *gocui.View) error {
+	current := t.u.Boundary()
+	next := universe.Boundaries[0]
+	for i, b := range universe.Boundaries {
+		if b == current {
+			next = universe.Boundaries[(i+1)%len(universe.Boundaries)]
+			break
+		}
+	}
+	//the boundaries list contains the valid modes only
+	_ = t.u.SetBoundary(next)
+	return nil
+}
+
+//cmdTogglePreview calls by gocui key handler and toggles the preview of the next step changes
+func (t *ConsoleUI) cmdTogglePreview(_ *gocui.View) error {
+	t.preview = !t.preview
+	t.Refresh()
+	return nil
+}
+
+//cmdScrubBack calls by gocui key handler and shows the kept generation before the shown one in the focused panel
+func (t *ConsoleUI) cmdScrubBack(_ *gocui.View) error {
+	p := t.focused()
+	first, last, ok := p.u.History()
+	if !ok || !p.scrubbing && last == first {
+		t.notify("No past generations are kept")
+		return nil
+	}
+	if !p.scrubbing {
+		p.scrubbing, p.scrubGen = true, last
+	}
+	if p.scrubGen <= first {
+		p.scrubGen = first
+		t.notify("The oldest kept generation")
+	} else {
+		p.scrubGen--
+	}
+	t.Refresh()
+	return nil
+}
+
+//cmdScrubForward calls by gocui key handler and shows the kept generation after the shown one in the focused panel
+//scrubbing is over when the live generation is reached
+func (t *ConsoleUI) cmdScrubForward(_ *gocui.View) error {
+	p := t.focused()
+	if !p.scrubbing {
+		return nil
+	}
+	p.scrubGen++
+	if _, last, ok := p.u.History(); !ok || p.scrubGen >= last {
+		p.scrubbing = false
+		t.notify("Back to the live generation")
+	}
+	t.Refresh()
+	return nil
+}
+
+//cmdFork calls by gocui key handler and continues the simulation of the focused panel from the scrubbed generation
+//the later generations are dropped
+func (t *ConsoleUI) cmdFork(_ *gocui.View) error {
+	p := t.focused()
+	if !p.scrubbing {
+		t.notify("Scrub back with [ to fork")
+		return nil
+	}
+	t.stopSharedTicker()
+	p.scrubbing = false
+	if err := p.u.Fork(p.scrubGen); err != nil {
+		t.notify("The generation is no longer kept")
+		return nil
+	}
+	t.notify(fmt.Sprintf("Forked at generation %d", p.scrubGen))
+	return nil
+}
+
+//cmdToggleSeamIndicator calls by gocui key handler and toggles the marking of the wrapped field edges
+func (t *ConsoleUI) cmdToggleSeamIndicator(_ *gocui.View) error {
+	t.seamIndicator = !t.seamIndicator
+	t.Refresh()
+	return nil
+}
+
+//cmdToggleSquareCells calls by gocui key handler and switches the cells between one and two glyphs wide
+func (t *ConsoleUI) cmdToggleSquareCells(_ *gocui.View) error {
+	t.squareCells = !t.squareCells
+	t.Refresh()
+	return nil
+}
+
+//cmdToggleWrapGhosts calls by gocui key handler and toggles the ghosts of the cells across the wrapped edges
+func (t *ConsoleUI) cmdToggleWrapGhosts(_ *gocui.View) error {
+	t.wrapGhosts = !t.wrapGhosts
+	t.Refresh()
 	return nil
 }
 
 //cmdMouseClick calls by gocui mouse button is clicked and calls Inverse command fot the cell in the Universe
+//or paints the cells under the brush, the single cell is painted instead of inverted in the draw mode
 func (t *ConsoleUI) cmdMouseClick(v *gocui.View) error {
-	cx, cy := v.Cursor()
-	t.u.InverseCell(cx, cy)
+	cx, cy := t.clickedCell(v)
+	if t.tool != toolBrush {
+		t.drawShape(universe.Point{X: cx, Y: cy})
+		return nil
+	}
+	if t.brushSize == 0 && !t.drawMode {
+		t.u.InverseCell(cx, cy)
+		return nil
+	}
+	t.u.SetCells(t.brushPoints(cx, cy), true)
+	return nil
+}
+
+//cmdMouseErase calls by gocui when the right mouse button is clicked and erases the cells under the brush in the draw mode
+func (t *ConsoleUI) cmdMouseErase(v *gocui.View) error {
+	cx, cy := t.clickedCell(v)
+	if t.drawMode {
+		t.u.SetCells(t.brushPoints(cx, cy), false)
+	}
+	return nil
+}
+
+//clickedCell focuses the clicked panel and moves the cursor to the clicked cell, returns the cell
+func (t *ConsoleUI) clickedCell(v *gocui.View) (x int, y int) {
+	for i, p := range t.panels {
+		if p.name == v.Name() && i != t.focus {
+			t.setFocus(i)
+		}
+	}
+	x, y = t.focused().toField(v.Cursor())
+	if bottomUp(t.u) {
+		y = t.u.Options().Height - 1 - y
+	}
+	t.cursorX, t.cursorY = x, y
+	return x, y
+}
+
+//cmdToggleDrawMode calls by gocui key handler and switches the mouse between toggling and drawing the cells
+func (t *ConsoleUI) cmdToggleDrawMode(_ *gocui.View) error {
+	t.drawMode = !t.drawMode
 	return nil
 }
+
+//cmdPlacePattern returns the gocui key handler placing the loaded pattern at the cursor
+//the field is replaced by the pattern or the pattern is added to the field if merge is set
+func (t *ConsoleUI) cmdPlacePattern(merge bool) func(_ *gocui.View) error {
+	return func(_ *gocui.View) error {
+		for _, u := range t.targets() {
+			name, _ := u.Options().Advanced[universe.AdvPattern].(string)
+			if name == "" {
+				t.notify("No pattern loaded")
+				return nil
+			}
+			p := universe.Placement{Name: name, X: t.cursorX, Y: t.displayY(t.cursorY), Anchor: t.o.PatternAnchor}
+			if warnings := u.SettleLayout([]universe.Placement{p}, merge); len(warnings) > 0 {
+				t.notify("The pattern is clipped")
+			}
+		}
+		return nil
+	}
+}
+
+//cmdToggleCompare calls by gocui key handler and toggles the comparison with the reference area
+//the current field is pinned as the reference if there is no one
+func (t *ConsoleUI) cmdToggleCompare(_ *gocui.View) error {
+	if !t.compare && t.reference.Entities == nil {
+		return t.cmdPinReference(nil)
+	}
+	t.compare = !t.compare
+	t.Refresh()
+	return nil
+}
+
+//cmdPinReference calls by gocui key handler and pins the current field as the reference area
+func (t *ConsoleUI) cmdPinReference(_ *gocui.View) error {
+	t.SetReference(t.u.Area())
+	return nil
+}
+
+//cmdNextPanel calls by gocui key handler and moves the focus to the next battlefield panel
+func (t *ConsoleUI) cmdNextPanel(_ *gocui.View) error {
+	t.setFocus((t.focus + 1) % len(t.panels))
+	return nil
+}
+
+//cmdToggleLockstep calls by gocui key handler and toggles applying the simulation commands to all the universes
+func (t *ConsoleUI) cmdToggleLockstep(_ *gocui.View) error {
+	t.lockstep = !t.lockstep
+	t.renderStatus()
+	return nil
+}
+
+//setFocus focuses the i-th panel, the configuration and the status panels show its universe
+func (t *ConsoleUI) setFocus(i int) {
+	t.focus = i
+	t.u = t.panels[i].u
+	t.Refresh()
+}
+
+//cmdToggleInspect calls by gocui key handler and toggles the explanation of the cell at the cursor
+func (t *ConsoleUI) cmdToggleInspect(_ *gocui.View) error {
+	t.inspect = !t.inspect
+	t.renderStatus()
+	return nil
+}
+
+//cmdCursorMover returns the gocui key handler moving the inspected cell cursor by dx, dy within the field
+func (t *ConsoleUI) cmdCursorMover(dx int, dy int) func(_ *gocui.View) error {
+	return func(_ *gocui.View) error {
+		a := t.u.Area()
+		flip := bottomUp(t.u)
+		step := dy
+		if flip {
+			step = -step
+		}
+		if x := t.cursorX + dx; x >= 0 && x < a.Width {
+			t.cursorX = x
+		}
+		if y := t.cursorY + step; y >= 0 && y < a.Height {
+			t.cursorY = y
+		}
+		p := t.focused()
+		if v, err := t.g.View(p.name); err == nil {
+			cy := t.cursorY
+			if flip {
+				cy = a.Height - 1 - cy
+			}
+			_ = v.SetCursor(p.toView(t.cursorX, cy))
+		}
+		t.renderStatus()
+		return nil
+	}
+}
+
+//cmdPanner returns the gocui key handler shifting the window of the oversized field by the quarter of the view
+//the window is wrapped around the toroidal axes
+func (t *ConsoleUI) cmdPanner(dx int, dy int) func(_ *gocui.View) error {
+	return func(_ *gocui.View) error {
+		p := t.focused()
+		p.panX += dx * max(p.fieldW/max(p.cellW, 1)/4, 1)
+		p.panY += dy * max(p.fieldH/4, 1)
+		t.renderPanel(p)
+		return nil
+	}
+}
+
+//bottomUp checks if the universe coordinates origin is at the bottom left corner, so the field is displayed upside down
+func bottomUp(u universe.Universe) bool {
+	return u.Options().Advanced[universe.AdvOrigin] == universe.OriginBottomLeft
+}
+
+//displayY converts the area y coordinate to the displayed one by the origin option
+func (t *ConsoleUI) displayY(y int) int {
+	if bottomUp(t.u) {
+		return t.u.Options().Height - 1 - y
+	}
+	return y
+}
+
+//explainCell describes the neighbours of the cell at the cursor and its next state
+func (t *ConsoleUI) explainCell() (neighbours string, next string) {
+	a := t.u.Area()
+	if t.cursorX >= a.Width || t.cursorY >= a.Height {
+		return "-", "-"
+	}
+	n, nextLive := universe.CellForecast(a, t.u.Rule(), t.u.Boundary(), t.cursorX, t.cursorY)
+	live := bool(a.Entities[t.cursorY][t.cursorX])
+	switch {
+	case live && nextLive:
+		next = "survives"
+	case live:
+		next = "dies"
+	case nextLive:
+		next = "is born"
+	default:
+		next = "stays dead"
+	}
+	return fmt.Sprintf("%d", n), next
+}
+
+//cmdGrowBrush calls by gocui key handler and increases the brush radius
+func (t *ConsoleUI) cmdGrowBrush(_ *gocui.View) error {
+	if t.brushSize < maxBrushSize {
+		t.brushSize++
+	}
+	t.renderStatus()
+	return nil
+}
+
+//cmdShrinkBrush calls by gocui key handler and decreases the brush radius
+func (t *ConsoleUI) cmdShrinkBrush(_ *gocui.View) error {
+	if t.brushSize > 0 {
+		t.brushSize--
+	}
+	t.renderStatus()
+	return nil
+}
+
+//cmdToggleBrushShape calls by gocui key handler and switches the brush between the square and the circle
+func (t *ConsoleUI) cmdToggleBrushShape(_ *gocui.View) error {
+	t.brushCircle = !t.brushCircle
+	t.renderStatus()
+	return nil
+}
+
+//differs describes the count of the cells which differ from the reference area
+func (t *ConsoleUI) differs() string {
+	d, err := universe.Diff(t.u.Area(), t.reference)
+	if err != nil {
+		return "size mismatch"
+	}
+	return fmt.Sprintf("%v cells", len(d))
+}
+
+//cmdNextTool calls by gocui key handler and switches to the next draw tool, the started shape is dropped
+func (t *ConsoleUI) cmdNextTool(_ *gocui.View) error {
+	t.tool = (t.tool + 1) % drawTool(len(toolNames))
+	t.shapeStart = nil
+	t.renderStatus()
+	return nil
+}
+
+//drawShape starts the shape at the clicked cell or draws the live cells of the shape ending at it
+//the cells outside the field are ignored by the universe
+func (t *ConsoleUI) drawShape(p universe.Point) {
+	if t.shapeStart == nil {
+		t.shapeStart = &p
+		t.notify("Click the end cell")
+		t.renderStatus()
+		return
+	}
+	var points []universe.Point
+	switch t.tool {
+	case toolLine:
+		points = universe.Line(*t.shapeStart, p)
+	case toolRect, toolFilledRect:
+		points = universe.Rect(*t.shapeStart, p, t.tool == toolFilledRect)
+	}
+	t.shapeStart = nil
+	t.u.SetCells(points, true)
+}
+
+//brushDescr returns the brush description for the status panel
+func (t *ConsoleUI) brushDescr() string {
+	if t.tool != toolBrush {
+		if t.shapeStart != nil {
+			return fmt.Sprintf("%s from %d,%d", toolNames[t.tool], t.shapeStart.X, t.displayY(t.shapeStart.Y))
+		}
+		return toolNames[t.tool]
+	}
+	if t.brushSize == 0 {
+		return "single cell"
+	}
+	if t.brushCircle {
+		return fmt.Sprintf("circle r%d", t.brushSize)
+	}
+	return fmt.Sprintf("square r%d", t.brushSize)
+}
+
+//brushPoints returns the field points covered by the brush centred at x, y
+func (t *ConsoleUI) brushPoints(x int, y int) []universe.Point {
+	a := t.u.Area()
+	r := t.brushSize
+	points := make([]universe.Point, 0, (2*r+1)*(2*r+1))
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if t.brushCircle && dx*dx+dy*dy > r*r {
+				continue
+			}
+			px, py := x+dx, y+dy
+			if px < 0 || py < 0 || px >= a.Width || py >= a.Height {
+				continue
+			}
+			points = append(points, universe.Point{X: px, Y: py})
+		}
+	}
+	return points
+}
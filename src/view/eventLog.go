@@ -0,0 +1,167 @@
+package view
+
+import (
+	"encoding/json"
+	"io"
+	"simlife/src/universe"
+	"sync"
+	"time"
+)
+
+//the events written by EventLog
+const (
+	EventCreated  = "created"  //the universe is registered, the rule and the loaded pattern are logged
+	EventStarted  = "started"  //the simulation is started
+	EventStopped  = "stopped"  //the simulation is stopped by the user
+	EventFinished = "finished" //the simulation is finished, the reason and the peak population are logged
+)
+
+//Event is the single JSON line written by EventLog
+type Event struct {
+	Time           time.Time `json:"time"`
+	Event          string    `json:"event"`
+	Universe       int       `json:"universe"` //the index of the universe in the registration order
+	Generation     int       `json:"generation"`
+	Population     int       `json:"population"`
+	Rule           string    `json:"rule,omitempty"`
+	Pattern        string    `json:"pattern,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	Period         int       `json:"period,omitempty"`
+	PeakPopulation int       `json:"peakPopulation,omitempty"`
+	PeakGeneration int       `json:"peakGeneration,omitempty"`
+	Dropped        int       `json:"dropped,omitempty"` //the count of the events dropped before this one by the full queue
+}
+
+//eventQueueSize is the count of the events waiting to be written, the events beyond are dropped
+const eventQueueSize = 256
+
+//EventLog is the viewer which writes the significant events of the registered universes as JSON lines
+//the events are written by the own goroutine, so the slow file or pipe doesn't slow down the simulation
+type EventLog struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	watched []*watchedUniverse
+	lastErr error
+	queue   chan Event
+	dropped int //the events dropped since the last queued one
+	closed  bool
+	done    chan bool
+}
+
+//watchedUniverse is the universe state seen by EventLog on the last refresh
+type watchedUniverse struct {
+	u        universe.Universe
+	lastMode universe.RunningState
+	lastStep int
+	peak     universe.Status //the status with the highest live cells count since the last restart
+}
+
+//NewEventLog starts the goroutine writing the events, Close waits for the queued ones
+func NewEventLog(w io.Writer) *EventLog {
+	l := &EventLog{enc: json.NewEncoder(w), queue: make(chan Event, eventQueueSize), done: make(chan bool)}
+	go l.writer()
+	return l
+}
+
+//Register starts watching the universe and logs its creation
+func (l *EventLog) Register(u *universe.BaseUniverse) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	st := u.Status()
+	wu := &watchedUniverse{u: u, lastMode: st.RunningMode, peak: st}
+	l.watched = append(l.watched, wu)
+	pattern, _ := u.Options().Advanced[universe.AdvPattern].(string)
+	l.write(Event{Event: EventCreated, Universe: len(l.watched) - 1, Generation: st.IterationNum, Population: st.LiveCells,
+		Rule: u.Rule().String(), Pattern: pattern})
+}
+
+//Refresh logs the running mode changes of the watched universes
+func (l *EventLog) Refresh() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, wu := range l.watched {
+		st := wu.u.Status()
+		if st.IterationNum < wu.lastStep {
+			//the universe is cleared or restarted
+			wu.peak = st
+		}
+		wu.lastStep = st.IterationNum
+		if st.LiveCells > wu.peak.LiveCells {
+			wu.peak = st
+		}
+		e := Event{Universe: i, Generation: st.IterationNum, Population: st.LiveCells}
+		switch {
+		case st.RunningMode == wu.lastMode:
+		case st.RunningMode == universe.RunningStateRun:
+			e.Event = EventStarted
+		case st.RunningMode == universe.RunningStateFinished:
+			e.Event = EventFinished
+			e.Reason = string(st.FinishReason)
+			e.Period = st.Period
+			e.PeakPopulation, e.PeakGeneration = wu.peak.LiveCells, wu.peak.IterationNum
+		case wu.lastMode == universe.RunningStateRun && st.RunningMode == universe.RunningStateManual:
+			e.Event = EventStopped
+			e.PeakPopulation, e.PeakGeneration = wu.peak.LiveCells, wu.peak.IterationNum
+		}
+		if st.RunningMode != universe.RunningStateStep {
+			wu.lastMode = st.RunningMode
+		}
+		if e.Event != "" {
+			l.write(e)
+		}
+	}
+}
+
+func (l *EventLog) Start() {
+}
+
+//Close writes the queued events and stops the writer, the later events are ignored
+func (l *EventLog) Close() {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return
+	}
+	l.closed = true
+	close(l.queue)
+	l.mu.Unlock()
+	<-l.done
+}
+
+//writer encodes the queued events until the log is closed, the events are dropped after the error
+func (l *EventLog) writer() {
+	defer close(l.done)
+	for e := range l.queue {
+		if l.Err() != nil {
+			continue
+		}
+		if err := l.enc.Encode(e); err != nil {
+			l.mu.Lock()
+			l.lastErr = err
+			l.mu.Unlock()
+		}
+	}
+}
+
+//Err returns the last write error, the events are dropped after the error
+func (l *EventLog) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lastErr
+}
+
+//write queues the event with the current time, should be called under the lock
+//the event is dropped if the queue is full, the next queued one keeps the count of the dropped events
+func (l *EventLog) write(e Event) {
+	if l.lastErr != nil || l.closed {
+		return
+	}
+	e.Time = time.Now()
+	e.Dropped = l.dropped
+	select {
+	case l.queue <- e:
+		l.dropped = 0
+	default:
+		l.dropped++
+	}
+}
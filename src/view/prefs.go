@@ -0,0 +1,69 @@
+package view
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+//Prefs are the console UI preferences kept between the runs
+type Prefs struct {
+	ChangeFlash   bool `json:"changeFlash"`
+	SeamIndicator bool `json:"seamIndicator"`
+	WrapGhosts    bool `json:"wrapGhosts"`
+	Inspect       bool `json:"inspect"`
+	BrushSize     int  `json:"brushSize"`
+	BrushCircle   bool `json:"brushCircle"`
+	HoldToRun     bool `json:"holdToRun"`
+	DrawMode      bool `json:"drawMode"`
+	SquareCells   bool `json:"squareCells"`
+	//the viewport of the first battlefield, saved only when Options.SaveViewport is set
+	Viewport *Viewport `json:"viewport,omitempty"`
+}
+
+//Viewport is the cursor and the pan of the battlefield, it is restored for the field of the same dimensions only
+type Viewport struct {
+	Width   int `json:"width"`
+	Height  int `json:"height"`
+	CursorX int `json:"cursorX"`
+	CursorY int `json:"cursorY"`
+	PanX    int `json:"panX"`
+	PanY    int `json:"panY"`
+}
+
+//DefaultPrefsFile returns the preferences file in the user's config directory, the empty string if there is no one
+func DefaultPrefsFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "simlife", "prefs.json")
+}
+
+//loadPrefs reads the preferences file, ok is false if the file is missing or corrupted
+func loadPrefs(name string) (p Prefs, ok bool) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return p, false
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Prefs{}, false
+	}
+	if p.BrushSize < 0 || p.BrushSize > maxBrushSize {
+		p.BrushSize = 0
+	}
+	return p, true
+}
+
+//savePrefs writes the preferences file creating its directory
+func savePrefs(name string, p Prefs) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, data, 0644)
+}
@@ -0,0 +1,286 @@
+package view
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"simlife/src/universe"
+	"sort"
+	"sync"
+	"time"
+)
+
+//the commands sent by the attached clients
+const (
+	CommandRun   = "run"
+	CommandStop  = "stop"
+	CommandStep  = "step"
+	CommandClear = "clear"
+)
+
+const (
+	serverFrameInterval = time.Millisecond * 100 //the minimal interval between the frames streamed to the clients
+	serverWriteTimeout  = time.Second            //the client not reading the frames is detached
+)
+
+//Frame is the JSON line streamed by Server to the attached clients
+type Frame struct {
+	Generation int    `json:"generation"`
+	Population int    `json:"population"`
+	Mode       string `json:"mode"`
+	Reason     string `json:"reason,omitempty"`
+	Rule       string `json:"rule"`
+	Field      string `json:"field"` //the area encoded by universe.EncodeArea
+}
+
+//Capabilities is the first JSON line streamed by Server, the clients enable the features the server supports
+type Capabilities struct {
+	Version     string   `json:"version"`
+	Engine      string   `json:"engine"`  //the engine of the served universe
+	Engines     []string `json:"engines"` //the engines available in the build
+	Rules       []string `json:"rules"`   //the preset rules, any rule in the B/S notation is accepted
+	Boundaries  []string `json:"boundaries"`
+	Anchors     []string `json:"anchors"`
+	RenderModes []string `json:"renderModes"` //the field modes of the console UI
+	Commands    []string `json:"commands"`
+}
+
+//RenderModes is the list of the field modes of the console UI
+var RenderModes = []string{"cells", "square-cells", "changes", "seams", "ghosts", "compare", "preview"}
+
+//NewCapabilities returns the capabilities of the build serving the universe on the engine
+func NewCapabilities(engine string, engines []string) Capabilities {
+	c := Capabilities{
+		Version:     universe.Version(),
+		Engine:      engine,
+		Engines:     append([]string(nil), engines...),
+		RenderModes: RenderModes,
+		Commands:    []string{CommandRun, CommandStop, CommandStep, CommandClear},
+	}
+	sort.Strings(c.Engines)
+	for _, r := range universe.PresetRules {
+		c.Rules = append(c.Rules, r.String())
+	}
+	c.Rules = append(c.Rules, universe.QuadLifeRule.String())
+	for _, b := range universe.Boundaries {
+		c.Boundaries = append(c.Boundaries, string(b))
+	}
+	for _, a := range universe.Anchors {
+		c.Anchors = append(c.Anchors, string(a))
+	}
+	return c
+}
+
+//Command is the JSON line sent by the attached client, one of the Command constants
+type Command struct {
+	Command string `json:"command"`
+}
+
+//frameModes maps the running modes to the Frame modes
+var frameModes = map[universe.RunningState]string{
+	universe.RunningStateManual:   "manual",
+	universe.RunningStateStep:     "step",
+	universe.RunningStateRun:      "running",
+	universe.RunningStateFinished: "finished",
+}
+
+//Server is the viewer which streams the first registered universe to the clients attached to the Unix socket
+//the Capabilities line is followed by the frames, the clients control the simulation by the commands
+//the simulation goes on when they are detached
+type Server struct {
+	mu       sync.Mutex
+	u        universe.Universe
+	l        net.Listener
+	path     string
+	caps     Capabilities
+	clients  map[net.Conn]*client
+	lastSent time.Time
+	lastMode universe.RunningState
+	//the frame skipped by the throttling is sent by the flush after serverFrameInterval
+	flushPending bool
+}
+
+//client is the attached connection, the frames are written by its own goroutine, so the slow client can't stall the simulation
+//only the latest frame is kept while the client is writing the previous one
+type client struct {
+	conn   net.Conn
+	frames chan Frame
+}
+
+//push replaces the frame waiting to be written by f, should be called under the server lock
+func (c *client) push(f Frame) {
+	for {
+		select {
+		case c.frames <- f:
+			return
+		default:
+			//the writer is busy with the previous frame, the waiting one is outdated
+			select {
+			case <-c.frames:
+			default:
+			}
+		}
+	}
+}
+
+//NewServer listens on the Unix socket at path, the socket file is removed by Close
+//caps are sent to the clients on attaching
+func NewServer(path string, caps Capabilities) (*Server, error) {
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{l: l, path: path, caps: caps, clients: make(map[net.Conn]*client)}, nil
+}
+
+//Register starts streaming the universe, the other universes are ignored
+func (s *Server) Register(u *universe.BaseUniverse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.u == nil {
+		s.u = u
+		s.lastMode = u.Status().RunningMode
+	}
+}
+
+//Start accepts the clients in background until the server is closed
+func (s *Server) Start() {
+	go func() {
+		for {
+			conn, err := s.l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+}
+
+//Refresh streams the frame to the clients at most once in serverFrameInterval, the running mode changes are streamed at once
+//the frames are queued to the clients writers, so the slow clients don't stall the simulation
+func (s *Server) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.u == nil || len(s.clients) == 0 {
+		return
+	}
+	mode := s.u.Status().RunningMode
+	if since := time.Since(s.lastSent); mode == s.lastMode && since < serverFrameInterval {
+		//the last change is sent by the flush even if no refresh follows
+		if !s.flushPending {
+			s.flushPending = true
+			time.AfterFunc(serverFrameInterval-since, s.flush)
+		}
+		return
+	}
+	s.lastMode, s.lastSent = mode, time.Now()
+	f := s.frame()
+	for _, c := range s.clients {
+		c.push(f)
+	}
+}
+
+//flush sends the frame skipped by the throttling
+func (s *Server) flush() {
+	s.mu.Lock()
+	s.flushPending = false
+	s.mu.Unlock()
+	s.Refresh()
+}
+
+//Close stops accepting the clients, detaches the attached ones and removes the socket file
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.l.Close()
+	for conn := range s.clients {
+		s.detach(conn)
+	}
+	//the unix listener removes the socket file on close, the leftovers are removed anyway
+	_ = os.Remove(s.path)
+}
+
+//detach closes the client connection and stops its writer, should be called under the lock
+func (s *Server) detach(conn net.Conn) {
+	if c, ok := s.clients[conn]; ok {
+		_ = conn.Close()
+		close(c.frames)
+		delete(s.clients, conn)
+	}
+}
+
+//write writes the frames queued to the client until it is detached, the client not reading them is detached
+func (s *Server) write(c *client) {
+	enc := json.NewEncoder(c.conn)
+	for f := range c.frames {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(serverWriteTimeout))
+		if err := enc.Encode(f); err != nil {
+			s.mu.Lock()
+			s.detach(c.conn)
+			s.mu.Unlock()
+		}
+	}
+}
+
+//serve sends the capabilities and the current frame to the attached client and runs its commands until it is detached
+func (s *Server) serve(conn net.Conn) {
+	_ = conn.SetWriteDeadline(time.Now().Add(serverWriteTimeout))
+	if err := json.NewEncoder(conn).Encode(s.caps); err != nil {
+		_ = conn.Close()
+		return
+	}
+	c := &client{conn: conn, frames: make(chan Frame, 1)}
+	s.mu.Lock()
+	s.clients[conn] = c
+	if s.u != nil {
+		c.push(s.frame())
+	}
+	s.mu.Unlock()
+	go s.write(c)
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	for {
+		var cmd Command
+		if err := dec.Decode(&cmd); err != nil {
+			break
+		}
+		s.command(cmd)
+	}
+	s.mu.Lock()
+	s.detach(conn)
+	s.mu.Unlock()
+}
+
+//command runs the client command, the unknown commands are ignored
+func (s *Server) command(c Command) {
+	s.mu.Lock()
+	u := s.u
+	s.mu.Unlock()
+	if u == nil {
+		return
+	}
+	switch c.Command {
+	case CommandRun:
+		u.Run()
+	case CommandStop:
+		u.Stop()
+	case CommandStep:
+		u.Step()
+	case CommandClear:
+		u.Clear()
+	}
+}
+
+//frame returns the current frame of the universe, should be called under the lock
+func (s *Server) frame() Frame {
+	st := s.u.Status()
+	return Frame{
+		Generation: st.IterationNum,
+		Population: st.LiveCells,
+		Mode:       frameModes[st.RunningMode],
+		Reason:     string(st.FinishReason),
+		Rule:       s.u.Rule().String(),
+		Field:      universe.EncodeArea(s.u.Area()),
+	}
+}
@@ -0,0 +1,99 @@
+package view
+
+import (
+	"fmt"
+	"github.com/jroimartin/gocui"
+	"simlife/src/universe"
+	"strings"
+	"time"
+)
+
+//SpeedPreset is the named simulation interval set by the number key
+type SpeedPreset struct {
+	Name     string
+	Interval time.Duration
+}
+
+//maxSpeedPresets is the count of the number keys the presets are bound to
+const maxSpeedPresets = 9
+
+//DefSpeedPresets is the default speed presets in the number keys order
+var DefSpeedPresets = []SpeedPreset{
+	{"slow", 500 * time.Millisecond},
+	{"normal", universe.DefSimulationInterval},
+	{"fast", 20 * time.Millisecond},
+	{"turbo", 0},
+}
+
+//ParseSpeedPresets parses the comma separated list of the name=interval speed presets, for example "slow=1s,fast=10ms"
+func ParseSpeedPresets(s string) ([]SpeedPreset, error) {
+	var presets []SpeedPreset
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid speed preset %q, want name=interval", p)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(kv[1]))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid interval of the speed preset %q", p)
+		}
+		presets = append(presets, SpeedPreset{strings.TrimSpace(kv[0]), d})
+	}
+	if len(presets) > maxSpeedPresets {
+		return nil, fmt.Errorf("too many speed presets, at most %d are allowed", maxSpeedPresets)
+	}
+	return presets, nil
+}
+
+//speedPresets returns the configured speed presets, DefSpeedPresets if they are not set
+func (t *ConsoleUI) speedPresets() []SpeedPreset {
+	if t.o.SpeedPresets == nil {
+		return DefSpeedPresets
+	}
+	return t.o.SpeedPresets
+}
+
+//speedPresetKeys returns the key bindings of the speed presets to the number keys starting with 1
+func (t *ConsoleUI) speedPresetKeys() []keyBindings {
+	presets := t.speedPresets()
+	keys := make([]keyBindings, 0, len(presets))
+	for i := range presets {
+		kb := keyBindings{rune('1' + i), "", "", t.cmdSpeedPreset(i), ""}
+		if i == 0 {
+			kb.name, kb.descr = "1", "Speed preset"
+			if len(presets) > 1 {
+				kb.name = fmt.Sprintf("1-%d", len(presets))
+			}
+		}
+		keys = append(keys, kb)
+	}
+	return keys
+}
+
+//speedPresetName returns the name of the preset with the interval, the empty string if there is no such preset
+func (t *ConsoleUI) speedPresetName(d time.Duration) string {
+	for _, p := range t.speedPresets() {
+		if p.Interval == d {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+//cmdSpeedPreset returns the gocui key handler setting the interval of the preset i to the target universes
+func (t *ConsoleUI) cmdSpeedPreset(i int) func(_ *gocui.View) error {
+	return func(_ *gocui.View) error {
+		p := t.speedPresets()[i]
+		for _, u := range t.targets() {
+			o := u.Options()
+			o.Interval = p.Interval
+			//the preset intervals are validated by ParseSpeedPresets
+			_ = u.SetOptions(o)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package view
+
+import (
+	"fmt"
+	"github.com/logrusorgru/aurora"
+	"simlife/src/universe"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//StateGlyph is the glyph and the color (one of ColorNames) the live cells of the multi-color rule state are drawn with
+type StateGlyph struct {
+	Glyph string
+	Color string
+}
+
+//ParseStateGlyphs parses the comma separated list of the state=glyph:color entries, for example "1=#:green,2=@:red"
+//the states are the live cells colors of the multi-color rules starting with 1
+func ParseStateGlyphs(s string) (map[int]StateGlyph, error) {
+	glyphs := map[int]StateGlyph{}
+	for _, e := range strings.Split(s, ",") {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		kv := strings.SplitN(e, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid state glyph %q, want state=glyph:color", e)
+		}
+		state, err := strconv.Atoi(strings.TrimSpace(kv[0]))
+		if err != nil || state < 1 || state > universe.MaxColors {
+			return nil, fmt.Errorf("invalid state of the glyph %q, want 1-%d", e, universe.MaxColors)
+		}
+		if _, ok := glyphs[state]; ok {
+			return nil, fmt.Errorf("duplicate glyph of the state %d", state)
+		}
+		gc := strings.SplitN(kv[1], ":", 2)
+		if len(gc) != 2 || utf8.RuneCountInString(gc[0]) != 1 {
+			return nil, fmt.Errorf("invalid state glyph %q, want a single character glyph and the color", e)
+		}
+		if _, ok := colors[gc[1]]; !ok {
+			return nil, fmt.Errorf("unknown color of the state glyph %q, want one of %v", e, strings.Join(ColorNames, ", "))
+		}
+		glyphs[state] = StateGlyph{gc[0], gc[1]}
+	}
+	return glyphs, nil
+}
+
+//ValidateStateGlyphs checks the glyphs cover all the states of the rule and only them, the two-state rules accept no glyphs
+func ValidateStateGlyphs(glyphs map[int]StateGlyph, r universe.Rule) error {
+	for state := range glyphs {
+		if state > r.Colors {
+			return fmt.Errorf("the rule %v has no state %d", r, state)
+		}
+	}
+	for state := 1; state <= r.Colors; state++ {
+		if _, ok := glyphs[state]; !ok {
+			return fmt.Errorf("no glyph of the state %d of the rule %v", state, r)
+		}
+	}
+	return nil
+}
+
+//stateFillers returns the live cells fillers of the multi-color rules indexed by the state
+//the configured glyphs replace the default ones
+func stateFillers(glyphs map[int]StateGlyph) (fillers [universe.MaxColors + 1]string) {
+	fillers = [universe.MaxColors + 1]string{
+		"",
+		aurora.Green("█").BgBrightGreen().String(),
+		aurora.BrightRed("█").BgBrightRed().String(),
+		aurora.BrightBlue("█").BgBrightBlue().String(),
+		aurora.BrightYellow("█").BgBrightYellow().String(),
+	}
+	for state, g := range glyphs {
+		fillers[state] = aurora.Colorize(g.Glyph, colors[g.Color]).String()
+	}
+	return
+}
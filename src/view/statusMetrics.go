@@ -0,0 +1,224 @@
+package view
+
+import (
+	"fmt"
+	"simlife/src/universe"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+//the metrics of the status panel
+const (
+	MetricStep           = "step"
+	MetricPopulation     = "population"
+	MetricBirths         = "births"     //the cells born and died on the last step
+	MetricDensity        = "density"    //the share of the live cells in the field
+	MetricCentroid       = "centroid"   //the center of mass of the live cells
+	MetricVelocity       = "velocity"   //the centroid shift per step
+	MetricSparkline      = "sparkline"  //the population of the last observed steps
+	MetricSPS            = "sps"        //the steps per second measured by the status panel
+	MetricClusters       = "clusters"   //shown when the clusters are counted
+	MetricEvaluation     = "evaluation" //the average step evaluation time
+	MetricLastEvaluation = "last-evaluation"
+	MetricMode           = "mode"
+	MetricBrush          = "brush"
+)
+
+//StatusMetrics is the list of the status panel metrics
+var StatusMetrics = []string{MetricStep, MetricPopulation, MetricBirths, MetricDensity, MetricCentroid, MetricVelocity,
+	MetricSparkline, MetricSPS, MetricClusters, MetricEvaluation, MetricLastEvaluation, MetricMode, MetricBrush}
+
+//DefStatusMetrics is the default set of the status panel metrics in the display order
+var DefStatusMetrics = []string{MetricStep, MetricPopulation, MetricClusters, MetricEvaluation, MetricLastEvaluation,
+	MetricMode, MetricBrush}
+
+//spsInterval is the period the steps per second are measured over
+const spsInterval = time.Second
+
+//sparklineSize is the count of the populations drawn by the sparkline, it fits the status panel
+const sparklineSize = 16
+
+//sparklineBars are the sparkline glyphs from the lowest population to the highest
+var sparklineBars = []rune("▁▂▃▄▅▆▇█")
+
+//ParseStatusMetrics parses the comma separated list of the status panel metrics
+func ParseStatusMetrics(s string) ([]string, error) {
+	var metrics []string
+	for _, m := range strings.Split(s, ",") {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if m == "" {
+			continue
+		}
+		found := false
+		for _, known := range StatusMetrics {
+			found = found || m == known
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown status metric %q", m)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+//spsMeter measures the steps per second of the focused universe, accessed by the gocui main loop only
+type spsMeter struct {
+	u     universe.Universe
+	since time.Time
+	step  int
+	sps   float64
+}
+
+//measure updates the steps per second once in spsInterval, the measurement restarts when the universe is changed
+func (m *spsMeter) measure(u universe.Universe, step int) float64 {
+	now := time.Now()
+	if m.u != u || step < m.step {
+		*m = spsMeter{u: u, since: now, step: step}
+		return 0
+	}
+	if elapsed := now.Sub(m.since); elapsed >= spsInterval {
+		m.sps = float64(step-m.step) / elapsed.Seconds()
+		m.since, m.step = now, step
+	}
+	return m.sps
+}
+
+//velocityMeter measures the centroid velocity of the focused universe, accessed by the gocui main loop only
+type velocityMeter struct {
+	u      universe.Universe
+	since  time.Time
+	step   int
+	area   universe.Area
+	vx, vy float64
+	ok     bool
+}
+
+//measure updates the velocity once in spsInterval by the area taken at the previous update
+//the measurement restarts when the universe is changed
+func (m *velocityMeter) measure(u universe.Universe, step int) (vx float64, vy float64, ok bool) {
+	now := time.Now()
+	if m.u != u || step < m.step {
+		*m = velocityMeter{u: u, since: now, step: step, area: u.Area()}
+		return 0, 0, false
+	}
+	if now.Sub(m.since) >= spsInterval && step > m.step {
+		a := u.Area()
+		m.vx, m.vy, m.ok = universe.Velocity(m.area, a, u.Boundary(), step-m.step)
+		m.since, m.step, m.area = now, step, a
+	}
+	return m.vx, m.vy, m.ok
+}
+
+//populationRing keeps the populations of the last observed steps of the focused universe, accessed by the gocui main loop only
+//the status panel observes the running universe by the frames, so the fast running steps are sampled
+type populationRing struct {
+	u    universe.Universe
+	step int
+	pops [sparklineSize]int
+	next int //the index of the next population
+	n    int //the count of the kept populations
+}
+
+//add keeps the population of the step, the step observed again is ignored, the ring restarts when the universe is changed
+func (r *populationRing) add(u universe.Universe, step int, population int) {
+	if r.u != u || step < r.step {
+		*r = populationRing{u: u}
+	}
+	if r.n > 0 && step == r.step {
+		return
+	}
+	r.pops[r.next] = population
+	r.next = (r.next + 1) % sparklineSize
+	r.n = min(r.n+1, sparklineSize)
+	r.step = step
+}
+
+//sparkline draws the kept populations the oldest first, the bars are scaled between the lowest and the highest one
+func (r *populationRing) sparkline() string {
+	pops := make([]int, r.n)
+	for i := range pops {
+		pops[i] = r.pops[(r.next-r.n+i+sparklineSize)%sparklineSize]
+	}
+	lo, hi := 0, 0
+	for i, p := range pops {
+		if i == 0 || p < lo {
+			lo = p
+		}
+		if i == 0 || p > hi {
+			hi = p
+		}
+	}
+	var b strings.Builder
+	for _, p := range pops {
+		bar := 0
+		if hi > lo {
+			bar = (p - lo) * (len(sparklineBars) - 1) / (hi - lo)
+		}
+		b.WriteRune(sparklineBars[bar])
+	}
+	return b.String()
+}
+
+//renderMetric returns the status panel line of the metric, the empty string if the metric has nothing to show
+//should be called by the gocui main loop
+func (t *ConsoleUI) renderMetric(name string, s universe.Status) string {
+	switch name {
+	case MetricStep:
+		return t.renderProp("Step", "%v", s.IterationNum)
+	case MetricPopulation:
+		return t.renderProp("Live Cells", "%v", s.LiveCells)
+	case MetricBirths:
+		born, died := t.u.Changes()
+		return t.renderProp("Born/Died", "%v/%v", len(born), len(died))
+	case MetricDensity:
+		o := t.u.Options()
+		if o.Width*o.Height == 0 {
+			return ""
+		}
+		return t.renderProp("Density", "%.2f%%", float64(s.LiveCells)*100/float64(o.Width*o.Height))
+	case MetricCentroid:
+		a := t.u.Area()
+		x, y, ok := universe.Centroid(a, t.u.Boundary())
+		if !ok {
+			return t.renderProp("Centroid", "-")
+		}
+		if bottomUp(t.u) {
+			y = float64(a.Height-1) - y
+		}
+		return t.renderProp("Centroid", "%.1f, %.1f", x, y)
+	case MetricVelocity:
+		vx, vy, ok := t.velocity.measure(t.u, s.IterationNum)
+		if !ok {
+			return t.renderProp("Velocity", "-")
+		}
+		if bottomUp(t.u) {
+			vy = -vy
+		}
+		return t.renderProp("Velocity", "%.3f, %.3f", vx, vy)
+	case MetricSparkline:
+		t.populations.add(t.u, s.IterationNum, s.LiveCells)
+		return t.renderProp("Trend", "%v", t.populations.sparkline())
+	case MetricSPS:
+		return t.renderProp("Steps/sec", "%.1f", t.sps.measure(t.u, s.IterationNum))
+	case MetricClusters:
+		if s.Components == nil {
+			return ""
+		}
+		return t.renderProp("Clusters", "%v", len(s.Components))
+	case MetricEvaluation:
+		return t.renderProp("Evaluation time", "%v", s.AvgIterationTime.Round(time.Microsecond))
+	case MetricLastEvaluation:
+		return t.renderProp("Last evaluation", "%v", s.IterationTime.Round(time.Microsecond))
+	case MetricMode:
+		if s.FastRun {
+			return t.renderProp("Mode", "%v", fastRunDescr)
+		} else if atomic.LoadInt32(&t.sharedRun) == 1 && s.RunningMode != universe.RunningStateFinished {
+			return t.renderProp("Mode", "%v", sharedRunDescr)
+		}
+		return t.renderProp("Mode", "%v", runningStateDescr[s.RunningMode])
+	case MetricBrush:
+		return t.renderProp("Brush", "%v", t.brushDescr())
+	}
+	return ""
+}
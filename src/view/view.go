@@ -0,0 +1,112 @@
+package view
+
+import (
+	"fmt"
+	"github.com/jroimartin/gocui"
+	"github.com/logrusorgru/aurora"
+	"os"
+	"simlife/src/universe"
+)
+
+//Options represents the viewers' configurable options
+type Options struct {
+	Bell   bool //ring the terminal bell when the simulation is finished
+	MaxFPS int  //the display is redrawn at most MaxFPS times per second, 0 - on every universe change
+	//the fields with more cells are redrawn once a second to keep the UI responsive, 0 - no limit
+	MaxRenderCells int
+	//highlight the cells born and died on the last step for one frame
+	ChangeFlash bool
+	//mark the wrapped field edges and flash the edge crossed by the cells
+	SeamIndicator bool
+	//the indicator shown when the field is larger than the viewing area, the defaults are used if unset
+	CropMessage string
+	CropGlyph   string //shown instead of CropMessage when it doesn't fit the pane
+	CropColor   string //one of the ColorNames
+	CropAnchor  string //the part of the oversized field which is shown, one of CropAnchors
+	//the header title and colors (ColorNames), the defaults are used if unset
+	HeaderText    string
+	HeaderColor   string
+	HeaderBgColor string
+	//the file the UI preferences are loaded from and saved to on exit, the empty string disables the preferences
+	PrefsFile string
+	//the cursor and the pan of the first battlefield are saved to the preferences and restored for the field of the same size
+	SaveViewport bool
+	//the status panel metrics in the display order (one of StatusMetrics), DefStatusMetrics if nil
+	StatusMetrics []string
+	//the neighbours of the live cells clusters counted by the C key, universe.Connectivity8 if 0
+	Connectivity universe.Connectivity
+	//the point of the loaded pattern placed at the cursor, universe.AnchorTopLeft if empty
+	PatternAnchor universe.Anchor
+	//the intervals set by the number keys starting with 1, DefSpeedPresets if nil
+	SpeedPresets []SpeedPreset
+	//the glyphs of the live cells states of the multi-color rules replacing the default ones, see ParseStateGlyphs
+	StateGlyphs map[int]StateGlyph
+	//the corner of the generation label drawn on the copied image (one of universe.LabelCorners), no label if empty
+	ImageLabel string
+	//the label of the copied image shows the population too
+	ImageLabelPopulation bool
+	//print the JSON Summary when the simulation is finished instead of the progress (console output)
+	Summary bool
+}
+
+//default options
+const (
+	DefMaxFPS         = 30
+	DefMaxRenderCells = 1 << 22
+	DefCropMessage    = "The field size is larger than the viewing area"
+	DefCropGlyph      = "»"
+	DefCropColor      = "red"
+	DefHeaderText     = "This is \"The Life\" game simulation"
+	DefHeaderColor    = "black"
+	DefHeaderBgColor  = "cyan"
+)
+
+//crop anchors
+const (
+	CropAnchorTopLeft     = "top-left"
+	CropAnchorCenter      = "center"
+	CropAnchorBottomRight = "bottom-right"
+)
+
+//CropAnchors is the list of the crop anchors, the first is the default
+var CropAnchors = []string{CropAnchorTopLeft, CropAnchorCenter, CropAnchorBottomRight}
+
+//ColorNames is the list of the colors available for the indicators
+var ColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+//colors maps the ColorNames to the foreground colors
+var colors = map[string]aurora.Color{
+	"black":   aurora.BlackFg,
+	"red":     aurora.RedFg,
+	"green":   aurora.GreenFg,
+	"yellow":  aurora.YellowFg,
+	"blue":    aurora.BlueFg,
+	"magenta": aurora.MagentaFg,
+	"cyan":    aurora.CyanFg,
+	"white":   aurora.WhiteFg,
+}
+
+//attributes maps the ColorNames to the gocui colors
+var attributes = map[string]gocui.Attribute{
+	"black":   gocui.ColorBlack,
+	"red":     gocui.ColorRed,
+	"green":   gocui.ColorGreen,
+	"yellow":  gocui.ColorYellow,
+	"blue":    gocui.ColorBlue,
+	"magenta": gocui.ColorMagenta,
+	"cyan":    gocui.ColorCyan,
+	"white":   gocui.ColorWhite,
+}
+
+//DefaultOptions is the default viewers' configuration
+var DefaultOptions = Options{
+	Bell:           true,
+	MaxFPS:         DefMaxFPS,
+	MaxRenderCells: DefMaxRenderCells,
+	SeamIndicator:  true,
+}
+
+//bell rings the terminal bell
+func bell() {
+	_, _ = fmt.Fprint(os.Stdout, "\a")
+}